@@ -0,0 +1,92 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"strings"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+const (
+	attributeSeparator = ","
+	attributeAssign    = "="
+)
+
+// Returns the rules which have the attribute with the specified value in the same order as they were added.
+// The attributes are parsed from the tags, see Rule.Attributes.
+func (ignoreList *List) RulesWithAttribute(key string, value string) []Rule {
+	state := ignoreList.snapshot()
+	var rules []Rule
+	for i := range state.patternList {
+		p := &state.patternList[i]
+		if attributeValue, ok := p.attributes()[key]; ok && attributeValue == value {
+			rules = append(rules, p.rule())
+		}
+	}
+	return rules
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func (s *pattern) attributes() map[string]string {
+	return parseAttributes(s.tag)
+}
+
+// Parses the "key=value" items of the tag which are separated with ",".
+// The spaces around the keys and values are removed and the value can contain "=".
+// The items without "=" or with an empty key are not attributes, they are skipped.
+// It returns nil if the tag does not have any attribute.
+func parseAttributes(tag string) map[string]string {
+	if !strings.Contains(tag, attributeAssign) {
+		return nil
+	}
+	var attributes map[string]string
+	for _, item := range strings.Split(tag, attributeSeparator) {
+		key, value, ok := strings.Cut(item, attributeAssign)
+		key = strings.TrimSpace(key)
+		if !ok || len(key) == 0 {
+			continue
+		}
+		if attributes == nil {
+			attributes = make(map[string]string)
+		}
+		attributes[key] = strings.TrimSpace(value)
+	}
+	return attributes
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
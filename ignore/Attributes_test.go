@@ -0,0 +1,102 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func TestParseAttributes(t *testing.T) {
+	a := assert.New(t)
+	a.Nil(parseAttributes(""))
+	a.Nil(parseAttributes("Any text, really"))
+	a.Nil(parseAttributes("=value"))
+	a.Equal(map[string]string{"reason": "license", "owner": "audio-team", "severity": "warn"},
+		parseAttributes("reason=license, owner=audio-team, severity=warn"))
+	a.Equal(map[string]string{"note": "a=b", "empty": ""}, parseAttributes(" note = a=b ,text, empty=,"))
+	a.Equal(map[string]string{"key": "2"}, parseAttributes("key=1, key=2"))
+}
+
+func TestAttributes(t *testing.T) {
+	a := assert.New(t)
+	ignoreList, err := NewListFromString(strings.Join([]string{
+		"[reason=license, owner=audio-team, severity=warn] sounds/*",
+		"[owner=audio-team] not sounds/free/*",
+		"[Any text] *.tmp",
+		"[reason=size] *.wav",
+	}, "\n"))
+	a.NoError(err)
+
+	match := ignoreList.Explain("sounds/a.wav")
+	if a.NotNil(match.Rule) {
+		a.Equal("reason=license, owner=audio-team, severity=warn", match.Rule.Tag)
+		a.Equal(map[string]string{"reason": "license", "owner": "audio-team", "severity": "warn"}, match.Rule.Attributes)
+	}
+	if a.Len(match.Others, 1) {
+		a.Equal(map[string]string{"reason": "size"}, match.Others[0].Attributes)
+	}
+	match = ignoreList.Explain("a.tmp")
+	if a.NotNil(match.Rule) {
+		a.Equal("Any text", match.Rule.Tag)
+		a.Nil(match.Rule.Attributes)
+	}
+
+	// the tag is still returned as it is
+	ignored, tag := ignoreList.IsIgnoredEx("sounds/a.txt")
+	a.True(ignored)
+	a.Equal("reason=license, owner=audio-team, severity=warn", tag)
+
+	a.Equal([]string{"sounds" + pathSeparator + "*", "sounds" + pathSeparator + "free" + pathSeparator + "*"}, rulePatterns(ignoreList.RulesWithAttribute("owner", "audio-team")))
+	a.Equal([]string{"*.wav"}, rulePatterns(ignoreList.RulesWithAttribute("reason", "size")))
+	a.Empty(ignoreList.RulesWithAttribute("owner", "video-team"))
+	a.Empty(ignoreList.RulesWithAttribute("Any text", ""))
+}
+
+func TestAttributes_withTags(t *testing.T) {
+	a := assert.New(t)
+	ignoreList, err := NewListFromString("[reason=license, owner=audio] *.wav\n[release, reason=size] *.obj\n")
+	a.NoError(err)
+	// the patterns with the attributes only are not tagged
+	a.True(ignoreList.IsIgnoredFor("a.wav"))
+	a.True(ignoreList.IsIgnoredFor("a.wav", "release"))
+	a.True(ignoreList.IsIgnored("a.wav"))
+	a.True(ignoreList.IsIgnoredFor("a.obj", "release"))
+	a.False(ignoreList.IsIgnoredFor("a.obj", "debug"))
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
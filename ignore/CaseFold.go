@@ -0,0 +1,137 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// The flag which makes a pattern case-insensitive, it is written after "not " or "!".
+// Example: "not (?i)*.jpg" includes "photo.JPG" and "photo.jpg".
+const caseInsensitiveFlag = "(?i)"
+
+// Sets the case-insensitive mode of the list.
+// In this mode all the patterns match the paths regardless of the letter case, as if they had the "(?i)" flag.
+// The Unicode simple folding is used, i.e. "K", "k" and the Kelvin sign are the same letters.
+func (ignoreList *List) SetCaseInsensitive(caseInsensitive bool) {
+	ignoreList.update(func(state *listState) error {
+		state.caseInsensitive = caseInsensitive
+		state.reprocess()
+		return nil
+	})
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// Removes the case-insensitive flag from the pattern text,
+// the flag is also implied when the list is case-insensitive.
+func (state *listState) extractCaseFlag(line string) (string, bool) {
+	if strings.HasPrefix(line, caseInsensitiveFlag) {
+		return line[len(caseInsensitiveFlag):], true
+	}
+	return line, state.caseInsensitive
+}
+
+// Returns the query which is used by the pattern.
+func (s *pattern) queryFor(q *query) *query {
+	if s.caseInsensitive {
+		return q.folded()
+	}
+	return q
+}
+
+// Returns the query with the folded letter case, it is made once per query.
+func (q *query) folded() *query {
+	if q.foldedQuery == nil {
+		q.foldedQuery = &query{path: foldCase(q.path), isDir: q.isDir, separator: q.separator}
+		q.foldedQuery.foldedQuery = q.foldedQuery
+		for _, e := range q.elements {
+			q.foldedQuery.elements = append(q.foldedQuery.elements, foldCase(e))
+		}
+	}
+	return q.foldedQuery
+}
+
+// Replaces each letter with the same representative of its Unicode simple folding orbit,
+// so two strings are equal after folding if strings.EqualFold says they are equal.
+func foldCase(str string) string {
+	for i, r := range str {
+		if foldRune(r) != r {
+			var builder strings.Builder
+			builder.Grow(len(str))
+			builder.WriteString(str[:i])
+			for _, r := range str[i:] {
+				builder.WriteRune(foldRune(r))
+			}
+			return builder.String()
+		}
+	}
+	return str
+}
+
+// Returns the smallest lower case letter of the folding orbit
+// or the smallest letter if the orbit does not contain lower case ones.
+func foldRune(r rune) rune {
+	if r < utf8.RuneSelf {
+		if 'A' <= r && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return r
+	}
+	lower := rune(-1)
+	smallest := r
+	for f := r; ; {
+		if unicode.IsLower(f) && (lower == -1 || f < lower) {
+			lower = f
+		}
+		if f < smallest {
+			smallest = f
+		}
+		if f = unicode.SimpleFold(f); f == r {
+			break
+		}
+	}
+	if lower != -1 {
+		return lower
+	}
+	return smallest
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
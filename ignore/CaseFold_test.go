@@ -0,0 +1,139 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func TestFoldCase(t *testing.T) {
+	a := assert.New(t)
+	a.Equal("photo.jpg", foldCase("Photo.JPG"))
+	a.Equal("folder/file", foldCase("folder/file"))
+	a.Equal("straße", foldCase("STRAßE"))
+	a.Equal("кот", foldCase("КоТ"))
+	// the Kelvin sign and the long s are in the same orbits as "k" and "s"
+	a.Equal(foldCase("ks"), foldCase("Kſ"))
+	a.Equal(foldCase("ΣΑΣ"), foldCase("σας"))
+}
+
+func TestCaseInsensitiveFlag(t *testing.T) {
+	a := assert.New(t)
+	ignoreList, err := NewListFromLines([]string{"(?i)*.JPG", "(?i)Folder1/*", "(?i)FOLDER2/file1", "*.Png"})
+	a.NoError(err)
+
+	a.True(ignoreList.IsIgnored("photo.jpg"))
+	a.True(ignoreList.IsIgnored("photo.Jpg"))
+	a.True(ignoreList.IsIgnored("folder1/file1"))
+	a.True(ignoreList.IsIgnored("FOLDER1/file1"))
+	a.True(ignoreList.IsIgnored("folder2/FILE1"))
+	a.False(ignoreList.IsIgnored("folder2/file2"))
+	// the patterns without the flag are case-sensitive
+	a.True(ignoreList.IsIgnored("image.Png"))
+	a.False(ignoreList.IsIgnored("image.png"))
+
+	rules := ignoreList.Rules()
+	if a.Len(rules, 4) {
+		a.Equal("*.jpg", rules[0].Pattern)
+		a.True(rules[0].CaseInsensitive)
+		a.Equal("*.Png", rules[3].Pattern)
+		a.False(rules[3].CaseInsensitive)
+	}
+}
+
+func TestCaseInsensitiveFlag_include(t *testing.T) {
+	a := assert.New(t)
+	ignoreList, err := NewListFromLines([]string{"[tag] not (?i)folder1/*.ex", "folder*", "!(?i)*Test*Data*"})
+	a.NoError(err)
+	res, tag := ignoreList.IsIgnoredEx("FOLDER1/A.EX")
+	a.False(res)
+	a.Equal("tag", tag)
+	a.False(ignoreList.IsIgnored("folder2/testing/data"))
+	a.True(ignoreList.IsIgnored("folder2/file1"))
+}
+
+func TestSetCaseInsensitive(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := NewList()
+	ignoreList.AddPattern("*.Ex")
+	ignoreList.SetCaseInsensitive(true)
+	ignoreList.AddPattern("Folder1/")
+	ignoreList.AddPattern("FOLDER2/File1")
+
+	// the patterns which are added before are changed too
+	a.True(ignoreList.IsIgnored("a.Ex"))
+	a.True(ignoreList.IsIgnored("a.ex"))
+	a.True(ignoreList.IsIgnored("folder1/a"))
+	a.True(ignoreList.IsIgnored("folder2/file1"))
+
+	// the loaded patterns are case-insensitive too
+	a.NoError(ignoreList.LoadFromReader(strings.NewReader("*.Ex\n(?i)Folder1/")))
+	a.True(ignoreList.IsIgnored("a.ex"))
+	a.False(ignoreList.IsIgnored("folder2/file1"))
+
+	// the flag of the pattern is kept
+	ignoreList.SetCaseInsensitive(false)
+	a.False(ignoreList.IsIgnored("a.ex"))
+	a.True(ignoreList.IsIgnored("a.Ex"))
+	a.True(ignoreList.IsIgnored("folder1/a"))
+}
+
+func TestCaseInsensitive_gitignore(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := newGitignoreList(a, "(?i)*.JPG", "!(?i)Keep[A-C].jpg", "(?i)/Build/")
+	a.True(ignoreList.IsIgnored("photos/a.jpg"))
+	a.False(ignoreList.IsIgnored("photos/keepb.JPG"))
+	a.True(ignoreList.IsIgnored("photos/keepd.JPG"))
+	a.True(ignoreList.IsIgnored("BUILD/"))
+	a.True(ignoreList.IsIgnored("build/file"))
+	a.False(ignoreList.IsIgnored("src/build/file"))
+}
+
+func TestCaseInsensitive_walk(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := NewList()
+	ignoreList.SetCaseInsensitive(true)
+	ignoreList.AddPattern("FOLDER2/*")
+	q, err := ignoreList.snapshot().query("folder2/")
+	a.NoError(err)
+	a.True(ignoreList.snapshot().isFolderPruned(q))
+	ignoreList.AddPattern("not folder2/Folder1/*")
+	a.False(ignoreList.snapshot().isFolderPruned(q))
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
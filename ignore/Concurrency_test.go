@@ -0,0 +1,147 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"sync"
+	"testing"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// Run it with -race flag to check the data races.
+func TestConcurrentReloadAndLookup(t *testing.T) {
+	a := assert.New(t)
+	ignoreList, err := NewListFromLines([]string{"folder1/*", "not folder1/file2"})
+	a.NoError(err)
+	fpList := filePathList()
+
+	var readers sync.WaitGroup
+	var writers sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				// every snapshot ignores "folder1/file1" and includes "folder1/file2"
+				a.True(ignoreList.IsIgnored(fpList[0]))
+				a.False(ignoreList.IsIgnored(fpList[1]))
+				ignoreList.Explain(fpList[2])
+				ignoreList.Compile().IsIgnored(fpList[3])
+				ignoreList.Rules()
+			}
+		}()
+	}
+	for i := 0; i < 4; i++ {
+		writers.Add(1)
+		go func(i int) {
+			defer writers.Done()
+			for j := 0; j < 50; j++ {
+				lines := fmt.Sprintf("folder1/*\nnot folder1/file2\nfolder%d/*", j)
+				a.NoError(ignoreList.LoadFromReader(strings.NewReader(lines)))
+				a.NoError(ignoreList.AddPattern(fmt.Sprintf("[tag%d] *.ex%d", i, j)))
+				other, _ := NewListFromLines([]string{"folder1/*", fmt.Sprintf("folder2/folder%d/*", j)})
+				ignoreList.Combine(other)
+				ignoreList.SetEvaluation(EvaluationIncludeFirst)
+			}
+		}(i)
+	}
+	writers.Wait()
+	close(stop)
+	readers.Wait()
+}
+
+// The filtered list has the patterns which are processed with its own settings
+func TestConcurrentWithTags(t *testing.T) {
+	a := assert.New(t)
+	ignoreList, err := NewListFromLines([]string{"Folder1/*", "[release] *.Ex"})
+	a.NoError(err)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			ignoreList.SetCaseInsensitive(i%2 == 0)
+		}
+	}()
+	for i := 0; i < 200; i++ {
+		state := ignoreList.WithTags("release").snapshot()
+		for _, p := range state.patternList {
+			a.Equal(state.caseInsensitive, p.caseInsensitive)
+		}
+	}
+	<-done
+}
+
+// The snapshot is not changed when the list is changed
+func TestSnapshotImmutable(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := NewList()
+	a.NoError(ignoreList.AddPattern("folder1/*"))
+	a.NoError(ignoreList.AddPattern("folder2/*"))
+	snapshot := ignoreList.snapshot()
+
+	a.NoError(ignoreList.AddPattern("folder3/*"))
+	other, _ := NewListFromLines([]string{"[tag] folder1/*"})
+	ignoreList.Combine(other)
+	a.Error(ignoreList.AddPattern("[tag"))
+	ignoreList.Clear()
+	a.NoError(ignoreList.AddPattern("folder4/*"))
+
+	if a.Len(snapshot.patternList, 2) {
+		a.Equal("", snapshot.patternList[0].tag)
+		a.Equal("folder2"+pathSeparator, snapshot.patternList[1].prefix)
+	}
+	a.Len(ignoreList.snapshot().patternList, 1)
+}
+
+func TestZeroList(t *testing.T) {
+	a := assert.New(t)
+	var ignoreList List
+	a.False(ignoreList.IsIgnored("folder1/file1"))
+	a.Equal(EvaluationIncludeFirst, ignoreList.Evaluation())
+	a.NoError(ignoreList.AddPattern("folder1/*"))
+	a.True(ignoreList.IsIgnored("folder1/file1"))
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
@@ -0,0 +1,169 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"strings"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+const (
+	blockStart = "[@if "
+	blockEnd   = "[@end]"
+)
+
+// Returns new ignore list with the patterns which are active for the specified tags.
+// A pattern is active if it does not have tags (see Rule.Tags) or one of its tags is specified
+// and all the conditions of the blocks which contain it are the specified tags, see List.
+// The new list has the same settings, the changes of this list do not change the new one.
+func (ignoreList *List) WithTags(tags ...string) *List {
+	snapshot := ignoreList.snapshot()
+	state := *snapshot
+	set := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		set[tag] = true
+	}
+	state.patternList = nil
+	for _, p := range snapshot.patternList {
+		if p.isActive(set) {
+			state.patternList = append(state.patternList, p)
+		}
+	}
+	list := &List{}
+	list.state.Store(&state)
+	return list
+}
+
+// It returns true if the given file path is ignored by the patterns which are active for the specified tags.
+// It is a shortcut for a single check, use WithTags for several checks with the same tags.
+func (ignoreList *List) IsIgnoredFor(filePath string, tags ...string) bool {
+	return ignoreList.WithTags(tags...).IsIgnored(filePath)
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// The attributes of the tag are not the tags, so the pattern with the attributes only is active for any tags.
+func (s *pattern) isActive(tags map[string]bool) bool {
+	if patternTags := s.tags(); len(patternTags) != 0 && !hasTag(patternTags, tags) {
+		return false
+	}
+	for _, condition := range s.conditions {
+		if !tags[condition] {
+			return false
+		}
+	}
+	return true
+}
+
+func hasTag(patternTags []string, tags map[string]bool) bool {
+	for _, tag := range patternTags {
+		if tags[tag] {
+			return true
+		}
+	}
+	return false
+}
+
+// The block of the patterns which are active only for the condition tag.
+type block struct {
+	condition string
+	origin    Origin
+}
+
+// Returns the condition of the block start line, it returns false if the line is not a block start.
+func parseBlockStart(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, blockStart) || !strings.HasSuffix(line, "]") {
+		return "", false
+	}
+	return strings.TrimSpace(line[len(blockStart) : len(line)-1]), true
+}
+
+func isBlockEnd(line string) bool {
+	return strings.TrimSpace(line) == blockEnd
+}
+
+// Processes the block lines, it returns false if the line is not a block line.
+// The blocks before the fileBlocks are opened in the files which include the current one.
+func (l *loader) processBlock(line string, origin Origin, fileBlocks int) (bool, error) {
+	if condition, ok := parseBlockStart(line); ok {
+		if len(condition) == 0 {
+			return true, newParseError(origin, strings.Index(origin.Text, blockStart)+1, ErrBlockCondition)
+		}
+		l.blocks = append(l.blocks, block{condition: condition, origin: origin})
+		l.updateConditions()
+		return true, nil
+	}
+	if isBlockEnd(line) {
+		if len(l.blocks) == fileBlocks {
+			return true, newParseError(origin, strings.Index(origin.Text, blockEnd)+1, ErrBlockNotOpened)
+		}
+		l.blocks = l.blocks[:len(l.blocks)-1]
+		l.updateConditions()
+		return true, nil
+	}
+	return false, nil
+}
+
+// Reports the blocks which are not closed in the current file and closes them.
+func (l *loader) closeBlocks(fileBlocks int) error {
+	if len(l.blocks) == fileBlocks {
+		return nil
+	}
+	var err error
+	for _, b := range l.blocks[fileBlocks:] {
+		parseErr := newParseError(b.origin, strings.Index(b.origin.Text, blockStart)+1, ErrBlockNotClosed)
+		if !l.state.lenient {
+			err = parseErr
+			break
+		}
+		l.parseErrors = append(l.parseErrors, parseErr)
+	}
+	l.blocks = l.blocks[:fileBlocks]
+	l.updateConditions()
+	return err
+}
+
+// The patterns share the conditions, so the new slice is made for every change.
+func (l *loader) updateConditions() {
+	l.conditions = nil
+	for _, b := range l.blocks {
+		l.conditions = append(l.conditions, b.condition)
+	}
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
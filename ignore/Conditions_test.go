@@ -0,0 +1,211 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+const conditionsTestData = `*.tmp
+[debug] *.pdb
+[@if release]
+*.log
+  [@if ci]
+logs/*
+  [@end]
+[release] not keep.log
+[@end]
+`
+
+func rulePatterns(rules []Rule) []string {
+	var patterns []string
+	for _, rule := range rules {
+		patterns = append(patterns, rule.Pattern)
+	}
+	return patterns
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func TestWithTags(t *testing.T) {
+	a := assert.New(t)
+	ignoreList, err := NewListFromString(conditionsTestData)
+	a.NoError(err)
+
+	// the checks without tags use all the patterns
+	a.True(ignoreList.IsIgnored("a.tmp"))
+	a.True(ignoreList.IsIgnored("a.pdb"))
+	a.True(ignoreList.IsIgnored("a.log"))
+	a.True(ignoreList.IsIgnored("logs/file"))
+
+	a.True(ignoreList.IsIgnoredFor("a.tmp"))
+	a.False(ignoreList.IsIgnoredFor("a.pdb"))
+	a.False(ignoreList.IsIgnoredFor("a.log"))
+	a.True(ignoreList.IsIgnoredFor("a.pdb", "debug"))
+	a.False(ignoreList.IsIgnoredFor("a.log", "debug"))
+
+	release := ignoreList.WithTags("release")
+	a.True(release.IsIgnored("a.tmp"))
+	a.False(release.IsIgnored("a.pdb"))
+	a.True(release.IsIgnored("a.log"))
+	a.False(release.IsIgnored("keep.log"))
+	a.False(release.IsIgnored("logs/file"))
+
+	ci := ignoreList.WithTags("release", "ci")
+	a.True(ci.IsIgnored("logs/file"))
+	a.False(ignoreList.IsIgnoredFor("logs/file", "ci"))
+
+	// the filtered list does not depend on the original one
+	ignoreList.AddPattern("*.obj")
+	a.False(release.IsIgnored("a.obj"))
+	a.True(ignoreList.IsIgnored("a.obj"))
+
+	rules := ci.Rules()
+	if a.Len(rules, 4) {
+		a.Nil(rules[0].Conditions)
+		a.Equal([]string{"release"}, rules[1].Conditions)
+		a.Equal([]string{"release", "ci"}, rules[2].Conditions)
+		a.Equal([]string{"release"}, rules[3].Conditions)
+	}
+
+	// the rules do not share the conditions with the list
+	ignoreList.Rules()[2].Conditions[0] = "debug"
+	a.True(ignoreList.IsIgnoredFor("a.log", "release"))
+	a.False(ignoreList.IsIgnoredFor("a.log", "debug"))
+}
+
+func TestWithTags_settings(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := NewList()
+	ignoreList.SetEvaluation(EvaluationLastMatch)
+	ignoreList.AddPattern("[release] folder/*")
+	ignoreList.AddPattern("[release] not folder/file")
+	ignoreList.AddPattern("[release] folder/file")
+	a.True(ignoreList.WithTags("release").IsIgnored("folder/file"))
+	a.False(ignoreList.WithTags("debug").IsIgnored("folder/file"))
+}
+
+func TestConditions_errors(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := NewList()
+	var parseErr *ParseError
+
+	err := ignoreList.LoadFromReader(strings.NewReader("file1\n[@end]\n"))
+	a.ErrorIs(err, ErrBlockNotOpened)
+	if a.ErrorAs(err, &parseErr) {
+		a.Equal(2, parseErr.Line)
+		a.Equal(1, parseErr.Column)
+	}
+	a.ErrorIs(ignoreList.LoadFromReader(strings.NewReader("[@if  ]\nfile1\n[@end]\n")), ErrBlockCondition)
+
+	err = ignoreList.LoadFromReader(strings.NewReader("[@if a]\n  [@if b]\nfile1\n[@end]\n"))
+	a.ErrorIs(err, ErrBlockNotClosed)
+	if a.ErrorAs(err, &parseErr) {
+		a.Equal(1, parseErr.Line)
+	}
+	a.Empty(ignoreList.Rules())
+
+	ignoreList.SetLenient(true)
+	err = ignoreList.LoadFromReader(strings.NewReader("[@if a]\n[@if b]\nfile1\n[@end]\n[@end]\n[@end]\nfile2\n[@if c]\nfile3\n"))
+	var parseErrors ParseErrors
+	if a.ErrorAs(err, &parseErrors) && a.Len(parseErrors, 2) {
+		a.ErrorIs(parseErrors[0], ErrBlockNotOpened)
+		a.Equal(6, parseErrors[0].Line)
+		a.ErrorIs(parseErrors[1], ErrBlockNotClosed)
+		a.Equal(8, parseErrors[1].Line)
+	}
+	a.Equal([]string{"file1", "file2", "file3"}, rulePatterns(ignoreList.Rules()))
+	a.True(ignoreList.IsIgnoredFor("file3", "c"))
+	a.False(ignoreList.IsIgnoredFor("file1", "a"))
+	a.True(ignoreList.IsIgnoredFor("file1", "a", "b"))
+}
+
+func TestConditions_include(t *testing.T) {
+	a := assert.New(t)
+	fsys := fstest.MapFS{
+		".ignore":        {Data: []byte("[@if release]\n@include release.ignore\n[@end]\n")},
+		"release.ignore": {Data: []byte("*.log\n[@if ci]\nlogs/*\n[@end]\n")},
+		"open.ignore":    {Data: []byte("[@if release]\n@include close.ignore\n")},
+		"close.ignore":   {Data: []byte("file1\n[@end]\n")},
+	}
+	ignoreList, err := NewListFromFS(fsys, ".ignore")
+	a.NoError(err)
+	a.False(ignoreList.IsIgnoredFor("a.log"))
+	a.True(ignoreList.IsIgnoredFor("a.log", "release"))
+	a.True(ignoreList.IsIgnoredFor("logs/file", "release", "ci"))
+
+	// the blocks must be closed in the same file
+	err = ignoreList.LoadFromFS(fsys, "open.ignore")
+	a.ErrorIs(err, ErrBlockNotOpened)
+	var parseErr *ParseError
+	if a.ErrorAs(err, &parseErr) {
+		a.Equal("close.ignore", parseErr.File)
+	}
+}
+
+func TestConditions_gitignore(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := NewListWithDialect(DialectGitignore)
+	a.NoError(ignoreList.LoadFromReader(strings.NewReader("[@if release]\n")))
+	a.True(ignoreList.IsIgnoredFor("@", "release"))
+}
+
+func TestWriteTo_conditions(t *testing.T) {
+	a := assert.New(t)
+	ignoreList, err := NewListFromString(conditionsTestData + "[@if ci]\nfile1\n[@end]\n")
+	a.NoError(err)
+	a.Equal(`*.tmp
+[debug] *.pdb
+[@if release]
+*.log
+[@if ci]
+logs/*
+[@end]
+[release] not keep.log
+[@end]
+[@if ci]
+file1
+[@end]
+`, ignoreList.String())
+	checkRoundTrip(a, ignoreList)
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
@@ -0,0 +1,152 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// Origin describes where a pattern came from.
+type Origin struct {
+	// Path of the file which contains the pattern.
+	// It is empty if the pattern was not loaded from a file.
+	File string
+	// Number of the line, the first line is 1.
+	// It is 0 if the pattern was added with AddPattern.
+	Line int
+	// The original text of the line or of the AddPattern argument.
+	Text string
+}
+
+// Rule is a description of a pattern in the ignore list.
+type Rule struct {
+	// The pattern text without tag and "not " or "!".
+	Pattern string
+	// True for the patterns which include files i.e. the patterns with "not " or "!".
+	Include bool
+	// The raw text of the tag without "[" and "]".
+	Tag string
+	// The items of the tag which are separated with "," e.g. "[lod, heavy]" gives "lod" and "heavy".
+	// The attributes are not in the list. It is nil if the tag does not have items.
+	Tags []string
+	// The "key=value" attributes of the tag e.g. "[reason=license, owner=audio-team]".
+	// It is nil if the tag does not have attributes.
+	Attributes map[string]string
+	Origin     Origin
+	// The text of the comment after the pattern without "#".
+	Comment string
+	// The conditions of the [@if tag] blocks which contain the pattern, from the outer block to the inner one.
+	Conditions []string
+	// True for the patterns which match regardless of the letter case,
+	// the Pattern is written with the folded letter case then.
+	CaseInsensitive bool
+}
+
+// Match is a result of the Explain and Match methods.
+type Match struct {
+	// The same as IsIgnored returns.
+	Ignored bool
+	// The rule which decided whether the path is ignored.
+	// It is nil if none of the patterns matched the path.
+	Rule *Rule
+	// The other rules which also matched the path but did not decide, in the same order as they are in the list.
+	Others []Rule
+	// The union of the tags of the Rule and the Others in the order of the rules in the list.
+	Tags []string
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// Returns all the rules of the ignore list in the same order as they were added.
+func (ignoreList *List) Rules() []Rule {
+	state := ignoreList.snapshot()
+	rules := make([]Rule, 0, len(state.patternList))
+	for i := range state.patternList {
+		rules = append(rules, state.patternList[i].rule())
+	}
+	return rules
+}
+
+// Explains why the given file path is ignored or not.
+// It is useful to find out which pattern of which file causes the result.
+func (ignoreList *List) Explain(filePath string) Match {
+	state := ignoreList.snapshot()
+	q, err := state.query(filePath)
+	if err != nil {
+		return Match{}
+	}
+	return state.explain(q)
+}
+
+// Explains why the given path is ignored or not like Explain does,
+// but the type of the path is specified explicitly instead of the trailing separator.
+// So the patterns for folders only (like "folder/") match the path if isDir is true.
+func (ignoreList *List) Match(filePath string, isDir bool) Match {
+	state := ignoreList.snapshot()
+	q, err := state.typedQuery(filePath, isDir)
+	if err != nil {
+		return Match{}
+	}
+	return state.explain(q)
+}
+
+func (state *listState) explain(q *query) Match {
+	var match Match
+	idx := state.decisivePattern(q)
+	if idx == -1 {
+		return match
+	}
+	rule := state.patternList[idx].rule()
+	match.Ignored = !rule.Include
+	match.Rule = &rule
+	for i := range state.patternList {
+		p := &state.patternList[i]
+		if i == idx {
+			match.Tags = appendTags(match.Tags, rule.Tags...)
+		} else if p.isMatched(q) || state.inherits() && p.isMatchedInTree(q) {
+			other := p.rule()
+			match.Others = append(match.Others, other)
+			match.Tags = appendTags(match.Tags, other.Tags...)
+		}
+	}
+	return match
+}
+
+func (s *pattern) rule() Rule {
+	return Rule{Pattern: s.String(), Include: s.include, Tag: s.tag, Tags: s.tags(), Attributes: s.attributes(),
+		Origin: s.origin, Comment: s.comment, Conditions: append([]string(nil), s.conditions...),
+		CaseInsensitive: s.caseInsensitive}
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
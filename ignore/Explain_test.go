@@ -0,0 +1,184 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func TestExplain_case1(t *testing.T) {
+	a := assert.New(t)
+	writeIgnoreListFile([]string{"[tag1] folder2/*", "", "[tag2] not folder2/folder1/*", "*1"})
+	ignoreList, err := NewListFromFile(filePath)
+	a.NoError(err)
+	fpList := filePathList()
+
+	match := ignoreList.Explain(fpList[2]) // "folder2/folder1/file1"
+	a.False(match.Ignored)
+	if a.NotNil(match.Rule) {
+		a.Equal(Rule{Pattern: "folder2" + pathSeparator + "folder1" + pathSeparator + "*", Include: true, Tag: "tag2",
+			Tags: []string{"tag2"}, Origin: Origin{File: filePath, Line: 3, Text: "[tag2] not folder2/folder1/*"}}, *match.Rule)
+	}
+	if a.Len(match.Others, 2) {
+		a.Equal(Rule{Pattern: "folder2" + pathSeparator + "*", Tag: "tag1", Tags: []string{"tag1"}, Origin: Origin{File: filePath, Line: 1, Text: "[tag1] folder2/*"}}, match.Others[0])
+		a.Equal(Rule{Pattern: "*1", Origin: Origin{File: filePath, Line: 4, Text: "*1"}}, match.Others[1])
+	}
+
+	match = ignoreList.Explain(fpList[3]) // "folder2/folder2/file1"
+	a.True(match.Ignored)
+	if a.NotNil(match.Rule) {
+		a.Equal("tag1", match.Rule.Tag)
+		a.Equal(1, match.Rule.Origin.Line)
+	}
+	a.Len(match.Others, 1)
+
+	removeIgnoreListFile()
+}
+
+func TestExplain_case2(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := NewList()
+	a.Equal(Match{}, ignoreList.Explain("folder1/file1"))
+
+	ignoreList.AddPattern("folder2/file1")
+	a.Equal(Match{}, ignoreList.Explain("folder1/file1"))
+
+	match := ignoreList.Explain("folder2/file1")
+	a.True(match.Ignored)
+	if a.NotNil(match.Rule) {
+		a.Equal(Rule{Pattern: "folder2" + pathSeparator + "file1", Origin: Origin{Text: "folder2/file1"}}, *match.Rule)
+	}
+	a.Nil(match.Others)
+}
+
+func TestExplain_case3(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := NewList()
+	ignoreList.SetEvaluation(EvaluationLastMatch)
+	ignoreList.AddPattern("folder1/")
+	ignoreList.AddPattern("!*file1")
+	ignoreList.AddPattern("folder1/*/file*")
+
+	match := ignoreList.Explain("folder1/folder2/file1")
+	a.True(match.Ignored)
+	if a.NotNil(match.Rule) {
+		a.Equal("folder1"+pathSeparator+"*"+pathSeparator+"file*", match.Rule.Pattern)
+	}
+	if a.Len(match.Others, 2) {
+		a.Equal("folder1"+pathSeparator+"*", match.Others[0].Pattern)
+		a.Equal("*file1", match.Others[1].Pattern)
+		a.True(match.Others[1].Include)
+	}
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func TestRules(t *testing.T) {
+	a := assert.New(t)
+	writeIgnoreListFile([]string{"  [tag1] folder1/*  ", "not folder1/file1"})
+	ignoreList, err := NewListFromFile(filePath)
+	a.NoError(err)
+	ignoreList.AddPattern("*.ex")
+
+	rules := ignoreList.Rules()
+	if a.Len(rules, 3) {
+		a.Equal(Rule{Pattern: "folder1" + pathSeparator + "*", Tag: "tag1", Tags: []string{"tag1"}, Origin: Origin{File: filePath, Line: 1, Text: "  [tag1] folder1/*  "}}, rules[0])
+		a.Equal(Rule{Pattern: "folder1" + pathSeparator + "file1", Include: true, Origin: Origin{File: filePath, Line: 2, Text: "not folder1/file1"}}, rules[1])
+		a.Equal(Rule{Pattern: "*.ex", Origin: Origin{Text: "*.ex"}}, rules[2])
+	}
+	a.Empty(NewList().Rules())
+	removeIgnoreListFile()
+}
+
+// The origin of the pattern from the other list wins
+func TestCombineOrigin(t *testing.T) {
+	a := assert.New(t)
+	writeIgnoreListFile([]string{"*.ex", "folder1/*"})
+	ignoreList1, err := NewListFromFile(filePath)
+	a.NoError(err)
+	writeIgnoreListFile([]string{"", "[tag] folder1/*"})
+	ignoreList2, err := NewListFromFile(filePath)
+	a.NoError(err)
+	removeIgnoreListFile()
+
+	ignoreList1.Combine(ignoreList2)
+	match := ignoreList1.Explain("folder1/file1")
+	if a.NotNil(match.Rule) {
+		a.Equal(Origin{File: filePath, Line: 2, Text: "[tag] folder1/*"}, match.Rule.Origin)
+	}
+	match = ignoreList1.Explain("file.ex")
+	if a.NotNil(match.Rule) {
+		a.Equal(Origin{File: filePath, Line: 1, Text: "*.ex"}, match.Rule.Origin)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	a := assert.New(t)
+	ignoreList, err := NewListFromLines([]string{"build/", "[tag] *build"})
+	a.NoError(err)
+
+	match := ignoreList.Match("build", true)
+	a.True(match.Ignored)
+	if a.NotNil(match.Rule) {
+		a.Equal(1, match.Rule.Origin.Line)
+	}
+	a.Empty(match.Others)
+
+	match = ignoreList.Match("build/", false)
+	a.True(match.Ignored)
+	if a.NotNil(match.Rule) {
+		a.Equal("tag", match.Rule.Tag)
+	}
+	a.Empty(match.Others)
+
+	a.Equal(Match{}, ignoreList.Match("../build", true))
+	a.Equal(Match{}, ignoreList.Match("", true))
+}
+
+func TestMatch_gitignore(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := newGitignoreList(a, "build/")
+	a.True(ignoreList.Match("build", true).Ignored)
+	a.False(ignoreList.Match("build", false).Ignored)
+	a.False(ignoreList.Match("build/", false).Ignored)
+	a.True(ignoreList.IsIgnoredDir("folder1/build"))
+	a.True(ignoreList.Match("folder1/build/file1", false).Ignored)
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
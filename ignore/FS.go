@@ -0,0 +1,123 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"io/fs"
+	"path"
+	"strings"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// It returns new ignore list even if an error is occurred.
+// See LoadFromFS
+func NewListFromFS(fsys fs.FS, name string) (*List, error) {
+	list := NewList()
+	if err := list.LoadFromFS(fsys, name); err != nil {
+		return list, err
+	}
+	return list, nil
+}
+
+// Loads ignore list data from the file of the file system, for example embed.FS or zip.Reader.
+// It works the same way as LoadFromFile.
+func (ignoreList *List) LoadFromFS(fsys fs.FS, name string) error {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return ignoreList.load(file, name, fsSource{fsys: fsys}, false)
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// It returns true if the given entry is in the ignore list otherwise false.
+// The path is a path of the file system i.e. its separator is always "/".
+// The folders are checked with the trailing separator,
+// so the patterns for folders only (like "folder/" of DialectGitignore) are processed correctly.
+func (ignoreList *List) IsIgnoredEntry(filePath string, entry fs.DirEntry) bool {
+	state := ignoreList.snapshot()
+	q, err := state.query(fsPath(filePath, entry.IsDir(), &state.options))
+	if err != nil {
+		return false
+	}
+	res, _ := state.isIgnored(q)
+	return res
+}
+
+// Walks the file tree of the file system like fs.WalkDir does
+// but it does not call the function for the ignored entries.
+// The entries are checked with the paths relative to the root,
+// the function gets the same paths as it gets from fs.WalkDir.
+// The root is never ignored.
+//
+// The ignored folders are skipped entirely if none of their children can be included,
+// otherwise their children are checked one by one.
+func (ignoreList *List) WalkFS(fsys fs.FS, root string, fn fs.WalkDirFunc) error {
+	return fs.WalkDir(fsys, root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil || filePath == root {
+			return fn(filePath, entry, err)
+		}
+		state := ignoreList.snapshot()
+		q, err := state.query(fsPath(relativePath(root, filePath), entry.IsDir(), &state.options))
+		if err != nil {
+			return fn(filePath, entry, nil)
+		}
+		if ignored, _ := state.isIgnored(q); !ignored {
+			return fn(filePath, entry, nil)
+		}
+		if entry.IsDir() && state.isFolderPruned(q) {
+			return fs.SkipDir
+		}
+		return nil
+	})
+}
+
+// Returns the path of the file system relative to the root.
+func relativePath(root string, filePath string) string {
+	root = path.Clean(root)
+	if root == "." {
+		return filePath
+	}
+	if filePath == root {
+		return "."
+	}
+	return strings.TrimPrefix(filePath, root+"/")
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
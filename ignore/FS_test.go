@@ -0,0 +1,147 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		".ignore":               {Data: []byte("folder2/*\nnot folder2/folder1/*\n[tag\n")},
+		"rules/.ignore":         {Data: []byte("folder2/*\nnot folder2/folder1/*\n")},
+		"folder1/file1":         {},
+		"folder1/file2":         {},
+		"folder2/folder1/file1": {},
+		"folder2/folder2/file1": {},
+		"build/file1":           {},
+	}
+}
+
+func walkedPaths(a *assert.Assertions, ignoreList *List, fsys fs.FS, root string) []string {
+	var paths []string
+	err := ignoreList.WalkFS(fsys, root, func(filePath string, entry fs.DirEntry, err error) error {
+		a.NoError(err)
+		paths = append(paths, filePath)
+		return nil
+	})
+	a.NoError(err)
+	return paths
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func TestLoadFromFS(t *testing.T) {
+	a := assert.New(t)
+	ignoreList, err := NewListFromFS(testFS(), "rules/.ignore")
+	a.NoError(err)
+	fpList := filePathList()
+	a.False(ignoreList.IsIgnored(fpList[2])) // "folder2/folder1/file1"
+	a.True(ignoreList.IsIgnored(fpList[3]))  // "folder2/folder2/file1"
+
+	_, err = NewListFromFS(testFS(), ".ignore")
+	var parseErr *ParseError
+	if a.True(errors.As(err, &parseErr)) {
+		a.Equal(".ignore", parseErr.File)
+		a.Equal(3, parseErr.Line)
+	}
+
+	_, err = NewListFromFS(testFS(), "not-exist")
+	a.True(errors.Is(err, fs.ErrNotExist))
+}
+
+func TestIsIgnoredEntry(t *testing.T) {
+	a := assert.New(t)
+	fsys := testFS()
+	ignoreList := newGitignoreList(a, "build/", "file2")
+	entries, err := fs.ReadDir(fsys, ".")
+	a.NoError(err)
+	var notIgnored []string
+	for _, entry := range entries {
+		if !ignoreList.IsIgnoredEntry(entry.Name(), entry) {
+			notIgnored = append(notIgnored, entry.Name())
+		}
+	}
+	a.Equal([]string{".ignore", "folder1", "folder2", "rules"}, notIgnored)
+
+	entries, err = fs.ReadDir(fsys, "folder1")
+	a.NoError(err)
+	if a.Len(entries, 2) {
+		a.False(ignoreList.IsIgnoredEntry("folder1/file1", entries[0]))
+		a.True(ignoreList.IsIgnoredEntry("folder1/file2", entries[1]))
+	}
+}
+
+func TestWalkFS_case1(t *testing.T) {
+	a := assert.New(t)
+	ignoreList, err := NewListFromLines([]string{"folder2/*", "not folder2/folder1/*", ".ignore", "rules/"})
+	a.NoError(err)
+	a.Equal([]string{
+		".",
+		"build",
+		"build/file1",
+		"folder1",
+		"folder1/file1",
+		"folder1/file2",
+		"folder2/folder1",
+		"folder2/folder1/file1",
+	}, walkedPaths(a, ignoreList, testFS(), "."))
+}
+
+// Paths are relative to the root
+func TestWalkFS_case2(t *testing.T) {
+	a := assert.New(t)
+	ignoreList, err := NewListFromLines([]string{"folder1/*"})
+	a.NoError(err)
+	a.Equal([]string{"folder2", "folder2/folder2", "folder2/folder2/file1"}, walkedPaths(a, ignoreList, testFS(), "folder2"))
+}
+
+func TestWalkFS_case3(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := NewList()
+	err := ignoreList.WalkFS(testFS(), "not-exist", func(filePath string, entry fs.DirEntry, err error) error {
+		return err
+	})
+	a.True(errors.Is(err, fs.ErrNotExist))
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
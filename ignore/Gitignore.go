@@ -0,0 +1,207 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// Dialect is a syntax of the patterns which an ignore list can parse.
+type Dialect int
+
+const (
+	// The "prefix*suffix" syntax described in the List documentation.
+	// It is used by default.
+	DialectNative Dialect = iota
+
+	// The .gitignore syntax.
+	//
+	// blank lines and lines which start with "#" are skipped, use "\#" for the patterns which start with "#".
+	// !pattern - Includes files which are matched by the pattern, use "\!" for the patterns which start with "!".
+	// ? - Matches any one symbol except "/".
+	// * - Matches anything except "/".
+	// [a-z] - Matches one symbol in the range, [!a-z] matches one symbol out of the range.
+	// **/pattern - Matches the pattern in all folders.
+	// pattern/** - Matches everything inside the folder.
+	// a/**/b - Matches zero or more folders between "a" and "b".
+	// /pattern - The pattern is relative to the root, the same is if the pattern contains "/" in the middle.
+	// pattern/ - Matches folders only. Use a trailing "/" in the path to tell the list that it is a folder.
+	// (?i)pattern - Matches regardless of the letter case, it is written after "!".
+	//
+	// The last matched pattern wins and it is not possible to include a file if its parent folder is ignored.
+	// The only separator is "/", the "\" is the escape symbol. Tags are not supported
+	// because the "[" is the start of the symbol range.
+	DialectGitignore
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+const anyFolders = "**"
+
+// The glob is a compiled pattern of the .gitignore syntax.
+type glob struct {
+	// path elements of the pattern, anyFolders matches zero or more elements
+	elements []string
+	dirOnly  bool
+}
+
+// Parses a pattern without negation.
+func newGlob(line string) (*glob, error) {
+	g := &glob{}
+	if strings.HasSuffix(line, "/") {
+		g.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	for _, e := range strings.Split(line, "/") {
+		if len(e) == 0 {
+			continue
+		}
+		e = fixRangeNegation(e)
+		if _, err := path.Match(e, ""); err != nil {
+			return nil, fmt.Errorf("incorrect path element <%s>: %w", e, err)
+		}
+		g.elements = append(g.elements, e)
+	}
+	if len(g.elements) == 0 {
+		return nil, errors.New("the pattern does not contain any path element")
+	}
+	if !anchored {
+		g.elements = append([]string{anyFolders}, g.elements...)
+	}
+	return g, nil
+}
+
+func (g *glob) isMatched(elements []string, isDir bool) bool {
+	if g.dirOnly && !isDir {
+		return false
+	}
+	return matchElements(g.elements, elements)
+}
+
+func matchElements(pattern []string, elements []string) bool {
+	for len(pattern) != 0 {
+		if pattern[0] == anyFolders {
+			rest := pattern[1:]
+			if len(rest) == 0 {
+				// "folder/**" does not match the "folder" itself
+				return len(elements) != 0
+			}
+			for i := 0; i <= len(elements); i++ {
+				if matchElements(rest, elements[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(elements) == 0 {
+			return false
+		}
+		if res, _ := path.Match(pattern[0], elements[0]); !res {
+			return false
+		}
+		pattern = pattern[1:]
+		elements = elements[1:]
+	}
+	return len(elements) == 0
+}
+
+// Replaces git range negation "[!" with the "[^" which is supported by the path.Match.
+func fixRangeNegation(element string) string {
+	if !strings.Contains(element, "[!") {
+		return element
+	}
+	out := []byte(element)
+	for i := 0; i < len(out)-1; i++ {
+		if out[i] == '\\' {
+			i++
+			continue
+		}
+		if out[i] == '[' && out[i+1] == '!' {
+			out[i+1] = '^'
+		}
+	}
+	return string(out)
+}
+
+// Removes trailing spaces if they are not escaped with "\".
+func trimGitignoreSpaces(line string) string {
+	end := len(line)
+	for end > 0 && line[end-1] == ' ' {
+		if end > 1 && line[end-2] == '\\' {
+			break
+		}
+		end--
+	}
+	return line[:end]
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func (state *listState) processGitignoreLine(inLine *string, origin Origin) *ParseError {
+	text := normalize(*inLine, state.normalizer)
+	line := trimGitignoreSpaces(text)
+	if len(strings.TrimSpace(line)) == 0 || strings.HasPrefix(line, "#") {
+		return nil
+	}
+	include := strings.HasPrefix(line, not2)
+	if include {
+		line = line[len(not2):]
+	}
+	line, caseInsensitive := state.extractCaseFlag(line)
+	column := strings.Index(text, line) + 1
+	if caseInsensitive {
+		line = foldCase(line)
+	}
+	g, err := newGlob(line)
+	if err != nil {
+		return newParseError(origin, column, err)
+	}
+	state.patternList = append(state.patternList, pattern{prefix: line, include: include, glob: g, origin: origin,
+		caseInsensitive: caseInsensitive, dialect: DialectGitignore})
+	return nil
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
@@ -0,0 +1,163 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func newGitignoreList(a *assert.Assertions, lines ...string) *List {
+	ignoreList := NewListWithDialect(DialectGitignore)
+	for _, line := range lines {
+		a.NoError(ignoreList.AddPattern(line))
+	}
+	return ignoreList
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func TestGitignoreComments(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := newGitignoreList(a, "# comment", "", "   ", "\\#file")
+	a.Len(ignoreList.snapshot().patternList, 1)
+	a.False(ignoreList.IsIgnored("# comment"))
+	a.True(ignoreList.IsIgnored("#file"))
+}
+
+func TestGitignoreBaseName(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := newGitignoreList(a, "*.ex", "file?")
+	a.True(ignoreList.IsIgnored("a.ex"))
+	a.True(ignoreList.IsIgnored("folder1/folder2/a.ex"))
+	a.True(ignoreList.IsIgnored("folder1/file1"))
+	a.True(ignoreList.IsIgnored("folder1/file1/a"))
+	a.False(ignoreList.IsIgnored("folder1/file12"))
+	a.False(ignoreList.IsIgnored("a.ex2"))
+}
+
+func TestGitignoreRange(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := newGitignoreList(a, "file[0-2]", "dir[!0-2]/")
+	a.True(ignoreList.IsIgnored("file0"))
+	a.False(ignoreList.IsIgnored("file3"))
+	a.True(ignoreList.IsIgnored("dir3/a"))
+	a.False(ignoreList.IsIgnored("dir2/a"))
+}
+
+func TestGitignoreAnchored(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := newGitignoreList(a, "/root.ex", "folder1/*.ex")
+	a.True(ignoreList.IsIgnored("root.ex"))
+	a.False(ignoreList.IsIgnored("folder2/root.ex"))
+	a.True(ignoreList.IsIgnored("folder1/a.ex"))
+	a.False(ignoreList.IsIgnored("folder1/folder2/a.ex"))
+	a.False(ignoreList.IsIgnored("folder2/folder1/a.ex"))
+}
+
+func TestGitignoreDoubleStar(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := newGitignoreList(a, "**/logs", "a/**/b", "build/**")
+	a.True(ignoreList.IsIgnored("logs"))
+	a.True(ignoreList.IsIgnored("x/y/logs"))
+	a.True(ignoreList.IsIgnored("a/b"))
+	a.True(ignoreList.IsIgnored("a/x/y/b"))
+	a.False(ignoreList.IsIgnored("a/x/y/c"))
+	a.True(ignoreList.IsIgnored("build/x"))
+	a.False(ignoreList.IsIgnored("build/"))
+}
+
+func TestGitignoreDirOnly(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := newGitignoreList(a, "build/")
+	a.True(ignoreList.IsIgnored("build/"))
+	a.True(ignoreList.IsIgnored("build/file"))
+	a.True(ignoreList.IsIgnored("folder1/build/file"))
+	a.False(ignoreList.IsIgnored("build"))
+}
+
+func TestGitignoreNegation(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := newGitignoreList(a, "*.ex", "!important.ex", "\\!file", "*.tmp", "!keep.tmp", "keep.tmp")
+
+	res, tag := ignoreList.IsIgnoredEx("important.ex")
+	a.False(res)
+	a.Equal("", tag)
+	a.True(ignoreList.IsIgnored("a.ex"))
+	a.True(ignoreList.IsIgnored("!file"))
+	a.True(ignoreList.IsIgnored("keep.tmp"))
+}
+
+func TestGitignoreExcludedParent(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := newGitignoreList(a, "folder1/", "!folder1/file1", "folder2/*", "!folder2/file1")
+	a.True(ignoreList.IsIgnored("folder1/file1"))
+	a.False(ignoreList.IsIgnored("folder2/file1"))
+	a.True(ignoreList.IsIgnored("folder2/file2"))
+}
+
+func TestGitignoreTrailingSpaces(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := newGitignoreList(a, "file1  ", "file2\\ ")
+	a.True(ignoreList.IsIgnored("file1"))
+	a.True(ignoreList.IsIgnored("file2 "))
+	a.False(ignoreList.IsIgnored("file2"))
+}
+
+func TestGitignoreIncorrectPattern(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := NewListWithDialect(DialectGitignore)
+	a.Error(ignoreList.AddPattern("file[1"))
+	a.Error(ignoreList.AddPattern("/"))
+	a.Len(ignoreList.snapshot().patternList, 0)
+}
+
+func TestGitignoreLoadFromFile(t *testing.T) {
+	a := assert.New(t)
+	writeIgnoreListFile([]string{"# comment", "folder2/*", "!folder2/folder1/"})
+	ignoreList := NewListWithDialect(DialectGitignore)
+	a.NoError(ignoreList.LoadFromFile(filePath))
+	fpList := filePathList()
+	a.False(ignoreList.IsIgnored(fpList[0])) // "folder1/file1"
+	a.False(ignoreList.IsIgnored(fpList[1])) // "folder1/file2"
+	a.False(ignoreList.IsIgnored(fpList[2])) // "folder2/folder1/file1"
+	a.True(ignoreList.IsIgnored(fpList[3]))  // "folder2/folder2/file1"
+	removeIgnoreListFile()
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
@@ -31,11 +31,12 @@ package ignore
 
 import (
 	"bufio"
-	"errors"
-	"fmt"
+	"io"
 	"os"
-	"regexp"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode/utf8"
 )
 
 /*********************************************************************************************************/
@@ -43,18 +44,32 @@ import (
 /*********************************************************************************************************/
 
 const (
-	not1                 = "not "
-	not2                 = "!"
-	pathSeparator string = string(os.PathSeparator)
+	not1                     = "not "
+	not2                     = "!"
+	commentStart             = "#"
+	pathSeparator     string = string(os.PathSeparator)
+	defaultSeparators        = "\\/:"
 )
 
-var path_sep_replacer_regex *regexp.Regexp = regexp.MustCompile("[\\\\/:]+")
-
 type pattern struct {
-	tag    string
-	prefix string
-	suffix string
-	isFile bool
+	tag     string
+	prefix  string
+	suffix  string
+	isFile  bool
+	include bool
+	glob    *glob
+	// literal segments between "*" symbols if the pattern has more than one "*",
+	// the first one is the same as prefix and the last one is the same as suffix.
+	segments []string
+	origin   Origin
+	// the text of the comment after the pattern without "#"
+	comment string
+	// the conditions of the blocks which contain the pattern, see List.WithTags
+	conditions []string
+	// the texts of the pattern are folded and it matches the folded paths, see foldCase
+	caseInsensitive bool
+	// the dialect of the origin text, the combined lists can have different dialects
+	dialect Dialect
 }
 
 func (s *pattern) HasPrefix() bool {
@@ -66,7 +81,43 @@ func (s *pattern) HasSuffix() bool {
 }
 
 func (s *pattern) IsEmpty() bool {
-	return !s.HasPrefix() && !s.HasSuffix()
+	return !s.HasPrefix() && !s.HasSuffix() && len(s.segments) == 0
+}
+
+// Returns the pattern text without tag and "not ".
+// The folder patterns are always written with "*" i.e. "some-folder/" is "some-folder/*".
+func (s *pattern) String() string {
+	if s.glob != nil || s.isFile {
+		return s.prefix
+	}
+	if len(s.segments) != 0 {
+		return strings.Join(s.segments, "*")
+	}
+	return s.prefix + "*" + s.suffix
+}
+
+func (s *pattern) isSame(other *pattern) bool {
+	if s.include != other.include || s.prefix != other.prefix || s.suffix != other.suffix ||
+		s.caseInsensitive != other.caseInsensitive {
+		return false
+	}
+	if len(s.segments) != len(other.segments) {
+		return false
+	}
+	for i := range s.segments {
+		if s.segments[i] != other.segments[i] {
+			return false
+		}
+	}
+	if len(s.conditions) != len(other.conditions) {
+		return false
+	}
+	for i := range s.conditions {
+		if s.conditions[i] != other.conditions[i] {
+			return false
+		}
+	}
+	return true
 }
 
 /*********************************************************************************************************/
@@ -79,9 +130,12 @@ func (s *pattern) IsEmpty() bool {
 // Example of the ignore patterns:
 //
 // some-folder/*- Ignores folder with name "some-folder" and all its children.
+// some-folder/ - The same as "some-folder/*", it does not ignore a file with name "some-folder".
+//                Use IsIgnoredDir or a trailing separator to check the folder itself.
 // some-folder/*.ex - Ignores files with extension ".ex" in folder "some-folder" and all its children..
 // *.ex - Ignores files with extension ".ex" in all folders.
 // some-folder/file - Ignores file with the name "file" in folder "some-folder". I.e. ignoring files by its full path.
+// some-folder/*/cache/*.ex - A pattern can contain several "*", the text between them must be found in the same order.
 //
 // For including files and folder you can use "not " or "!":
 //
@@ -95,23 +149,110 @@ func (s *pattern) IsEmpty() bool {
 // the "!" does not need that space "!some-folder".
 //
 // The path separator can be one of the following symbols: \ / :
+// It can be changed with SetOptions.
 //
 // The tag usage example:
 // [Any text] some-folder/*.ex
 // You can get the tag with method IsIgnoredEx
 // You can use the tags it as you wish for any porpoises.
-// The ignore list does not use tags at all, it just extract it for you.
+// The checks use all the patterns regardless of the tags,
+// use WithTags or IsIgnoredFor to check with the patterns of the specified tags only.
+// A tag can have several items separated with ",", e.g. "[lod, heavy] *.obj" has the "lod" and "heavy" tags.
+// Use MatchedTags to get the tags of all the patterns which match a path.
+// A tag can also describe the attributes of the pattern as "key=value" items separated with ",":
+// [reason=license, owner=audio-team, severity=warn] some-folder/*.ex
+// The attributes are parsed into Rule.Attributes, see also RulesWithAttribute.
+//
+// The patterns of an ignore file can be placed into blocks which are active for the specified tags only:
+// [@if release]
+// *.pdb
+// [@if ci]
+// logs/*
+// [@end]
+// [@end]
+// The "logs/*" pattern is active when both "release" and "ci" tags are specified, see WithTags.
+// The blocks are processed by the loading methods of DialectNative only and must be closed in the same file.
+//
+// The patterns are case-sensitive, use the "(?i)" flag to ignore the letter case:
+// [Any text] not (?i)*.jpg
+// It includes "photo.jpg" and "photo.JPG", see also SetCaseInsensitive.
+//
+// The lines which start with "#" are comments, use "\#" for the patterns which start with "#".
+// A pattern can also have a comment after it if the inline comments are enabled, see SetInlineComments:
+// some-folder/*  # build results
+// The comment of the pattern is kept, see Rule.
+//
+// An ignore file can include the patterns of other files in place of the directive line:
+// @include path/to/other.ignore
+// @include rules.d/*.ignore
+// The path is relative to the folder of the file which contains the directive
+// (or to the working folder for LoadFromReader), LoadFromFS includes the files from the same file system.
+// The path can be a pattern of filepath.Match, the matched files are included in the lexical order
+// and a pattern which does not match any file is not an error.
+// The patterns of the included files keep their own origin, so the errors and explanations name the included file.
+// A file can not include itself directly or through other files (ErrIncludeCycle)
+// and the includes can not be nested deeper than 16 files (ErrIncludeDepth).
+// The directives are processed by the loading methods of DialectNative only,
+// AddPattern and DialectGitignore process such a line as a pattern.
+//
+// The list can also read patterns in the .gitignore syntax, see DialectGitignore.
+//
+// The list keeps the patterns in the same order as they were added,
+// so it can choose the pattern which decides with different strategies, see Evaluation.
+//
+// The list can be used from several goroutines simultaneously, including changing and reloading it.
+// Every change makes a new immutable snapshot of the patterns and publishes it atomically,
+// so the checks which are in progress use the previous snapshot and never see a partial change.
 
 type List struct {
-	excludePatternList []pattern
-	includePatternList []pattern
+	// It serializes the changes, the checks do not use it.
+	mutex sync.Mutex
+	state atomic.Pointer[listState]
 }
 
+// The immutable snapshot of the list.
+type listState struct {
+	dialect         Dialect
+	evaluation      Evaluation
+	lenient         bool
+	inlineComments  bool
+	caseInsensitive bool
+	inheritance     bool
+	normalizer      Normalizer
+	options         Options
+	patternList     []pattern
+}
+
+var emptyListState = &listState{}
+
+// Evaluation is a strategy of choosing the pattern which decides whether a path is ignored.
+type Evaluation int
+
+const (
+	// EvaluationIncludeFirst for DialectNative and EvaluationLastMatch for DialectGitignore.
+	EvaluationDefault Evaluation = iota
+
+	// The include patterns are processed first, see IsIgnoredEx.
+	EvaluationIncludeFirst
+
+	// The last matched pattern wins regardless of whether it is an include or an exclude one.
+	// I.e. if there are 2 patterns like this: "not folder/1" and "folder/1" the result
+	// "folder/1" is ignored, but if the patterns have the reverse order then the file is included.
+	EvaluationLastMatch
+)
+
 // Returns new ignore list.
 func NewList() *List {
 	return &List{}
 }
 
+// Returns new ignore list which parses patterns with the specified dialect.
+func NewListWithDialect(dialect Dialect) *List {
+	list := &List{}
+	list.state.Store(&listState{dialect: dialect})
+	return list
+}
+
 // It returns new ignore list even if an error is occurred.
 func NewListFromFile(filePath string) (*List, error) {
 	list := NewList()
@@ -121,6 +262,22 @@ func NewListFromFile(filePath string) (*List, error) {
 	return list, nil
 }
 
+// Returns new ignore list with the patterns from the string, one pattern per line.
+// It returns new ignore list even if an error is occurred.
+func NewListFromString(str string) (*List, error) {
+	list := NewList()
+	if err := list.LoadFromReader(strings.NewReader(str)); err != nil {
+		return list, err
+	}
+	return list, nil
+}
+
+// Returns new ignore list with the patterns from the lines.
+// It returns new ignore list even if an error is occurred.
+func NewListFromLines(lines []string) (*List, error) {
+	return NewListFromString(strings.Join(lines, "\n"))
+}
+
 /*********************************************************************************************************/
 ///////////////////////////////////////////////////////////////////////////////////////////////////////////
 /*********************************************************************************************************/
@@ -129,44 +286,49 @@ func NewListFromFile(filePath string) (*List, error) {
 // It clears the struct before loading the file.
 // Use combine method if you want to combine 2 ignore lists.
 // It returns an error if occurred that describes the problem and makes the struct empty.
+// The problems with the patterns are returned as *ParseError.
+// In the lenient mode (see SetLenient) the incorrect lines are skipped
+// and all the problems are returned as ParseErrors.
 func (ignoreList *List) LoadFromFile(filePath string) error {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
-	ignoreList.Clear()
+	return ignoreList.load(file, filePath, osSource{}, false)
+}
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		if err = ignoreList.processLine(&line); err != nil {
-			ignoreList.Clear()
-			return err
-		}
-	}
-	if err = scanner.Err(); err != nil {
-		return err
-	}
-	return nil
+// Loads ignore list data from the reader, one pattern per line.
+// It works the same way as LoadFromFile, the problems with the patterns
+// are returned as *ParseError without the file name.
+func (ignoreList *List) LoadFromReader(reader io.Reader) error {
+	return ignoreList.load(reader, "", osSource{}, false)
 }
 
 // Combines 2 ignore lists in one this.
 // If this ignore list already contains th same pattern from other list
-// then pattern from other list will be used and will replace "tag".
+// then pattern from other list will be used and will replace "tag" and origin.
+// The replaced pattern is moved to the end of the list regardless of the evaluation,
+// so the patterns from other list have priority with EvaluationLastMatch even if it is set after combining.
 func (ignoreList *List) Combine(otherIgnoreList *List) *List {
-	ignoreList.combine(&ignoreList.includePatternList, &otherIgnoreList.includePatternList)
-	ignoreList.combine(&ignoreList.excludePatternList, &otherIgnoreList.excludePatternList)
+	other := otherIgnoreList.snapshot()
+	ignoreList.update(func(state *listState) error {
+		state.combine(other.patternList)
+		return nil
+	})
 	return ignoreList
 }
 
 // Adds a new pattern to the ignore list.
 // It does not check if the same patter is already exist.
+// The problem with the pattern is returned as *ParseError.
 func (ignoreList *List) AddPattern(pattern string) error {
-	if err := ignoreList.processLine(&pattern); err != nil {
-		return err
-	}
-	return nil
+	return ignoreList.update(func(state *listState) error {
+		if err := state.processLine(&pattern, Origin{Text: pattern}); err != nil {
+			return err
+		}
+		return nil
+	})
 }
 
 // It returns true if the given file path in the ignore list otherwise false.
@@ -183,32 +345,127 @@ func (ignoreList *List) IsIgnored(filePath string) bool {
 // so if the file is found in the include list the algorithm will not try to find it in the exclude list.
 // I.e. if there are 2 patterns like this: "not folder/1" and "folder/1" the result
 // "folder/1" is always included (i.e. the function returns false)
+//
+// The lists with EvaluationLastMatch use the last matched pattern instead, see SetEvaluation.
+// The lists in the inheritance mode also take the ignored parent folders into account, see SetInheritance.
+// The lists with DialectGitignore use the git rules by default: the last matched pattern wins
+// and a file can not be included if one of its parent folders is ignored.
 func (ignoreList *List) IsIgnoredEx(filePath string) (bool, string) {
-	if len(ignoreList.includePatternList) == 0 && len(ignoreList.excludePatternList) == 0 {
+	state := ignoreList.snapshot()
+	if len(state.patternList) == 0 {
 		return false, ""
 	}
 	//------------
-	res, idx := ignoreList.hasMatchedPattern(&filePath, &ignoreList.includePatternList)
-	if res {
-		return false, ignoreList.includePatternList[idx].tag
+	q, err := state.query(filePath)
+	if err != nil {
+		return false, ""
 	}
-	//------------
-	res, idx = ignoreList.hasMatchedPattern(&filePath, &ignoreList.excludePatternList)
-	if res {
-		return true, ignoreList.excludePatternList[idx].tag
+	return state.isIgnored(q)
+}
+
+// It returns true if the given folder path in the ignore list otherwise false.
+// The folder is checked the same way as the path with a trailing separator,
+// so the patterns for folders only (like "folder/") are processed correctly.
+// See IsIgnoredEx
+func (ignoreList *List) IsIgnoredDir(folderPath string) bool {
+	state := ignoreList.snapshot()
+	q, err := state.typedQuery(folderPath, true)
+	if err != nil {
+		return false
 	}
-	//------------
-	return false, ""
+	res, _ := state.isIgnored(q)
+	return res
+}
+
+// Returns the path which is matched with the patterns instead of the given one:
+// it is cleaned, it has the canonical separator and it is relative to the base folder, see Options.
+// The error is returned if the path goes outside the root or the base folder.
+func (ignoreList *List) Resolve(filePath string) (string, error) {
+	q, err := ignoreList.snapshot().query(filePath)
+	if err != nil {
+		return "", err
+	}
+	return q.path, nil
+}
+
+// Sets the strategy of choosing the pattern which decides whether a path is ignored.
+func (ignoreList *List) SetEvaluation(evaluation Evaluation) {
+	ignoreList.update(func(state *listState) error {
+		state.evaluation = evaluation
+		return nil
+	})
+}
+
+// Returns the actual strategy of choosing the pattern which decides whether a path is ignored,
+// it never returns EvaluationDefault.
+func (ignoreList *List) Evaluation() Evaluation {
+	return ignoreList.snapshot().actualEvaluation()
+}
+
+// Sets the lenient mode of loading the ignore list from a file.
+// In this mode the incorrect lines are skipped instead of aborting the loading.
+func (ignoreList *List) SetLenient(lenient bool) {
+	ignoreList.update(func(state *listState) error {
+		state.lenient = lenient
+		return nil
+	})
+}
+
+// Sets the mode of the comments after the patterns.
+// In this mode the text from "#" which is separated from the pattern with at least 2 spaces or a tab
+// is the comment of the pattern e.g. "some-folder/*  # build results", see Rule.Comment.
+// Without this mode (by default) the "#" is a part of the pattern like in "My Files #2/*".
+func (ignoreList *List) SetInlineComments(inlineComments bool) {
+	ignoreList.update(func(state *listState) error {
+		state.inlineComments = inlineComments
+		state.reprocess()
+		return nil
+	})
 }
 
 // Clears ignore list.
 func (ignoreList *List) Clear() {
-	if len(ignoreList.excludePatternList) != 0 {
-		ignoreList.excludePatternList = ignoreList.excludePatternList[:0]
+	ignoreList.update(func(state *listState) error {
+		state.patternList = nil
+		return nil
+	})
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// Returns the current snapshot of the list.
+func (ignoreList *List) snapshot() *listState {
+	if state := ignoreList.state.Load(); state != nil {
+		return state
+	}
+	return emptyListState
+}
+
+// Applies the change to a copy of the current snapshot and publishes the copy if the change succeeded.
+func (ignoreList *List) update(change func(state *listState) error) error {
+	ignoreList.mutex.Lock()
+	defer ignoreList.mutex.Unlock()
+	// The pattern list of the copy shares the array with the current snapshot.
+	// It is safe while the changes only append new patterns after the snapshot ones
+	// or replace the whole list, the snapshot never reads behind its length.
+	state := *ignoreList.snapshot()
+	if err := change(&state); err != nil {
+		return err
 	}
-	if len(ignoreList.includePatternList) != 0 {
-		ignoreList.includePatternList = ignoreList.includePatternList[:0]
+	ignoreList.state.Store(&state)
+	return nil
+}
+
+func (state *listState) actualEvaluation() Evaluation {
+	if state.evaluation != EvaluationDefault {
+		return state.evaluation
+	}
+	if state.dialect == DialectGitignore {
+		return EvaluationLastMatch
 	}
+	return EvaluationIncludeFirst
 }
 
 /*********************************************************************************************************/
@@ -225,10 +482,10 @@ func extractTag(str *string) (string, string, error) {
 	if strings.HasPrefix(*str, "[") {
 		idx := strings.Index(*str, "]")
 		if idx == -1 {
-			return outLine, outTag, errors.New("tag is not closed, you must use <]> symbol to close it")
+			return outLine, outTag, ErrTagNotClosed
 		}
 		if idx != strLen-1 {
-			outLine = (*str)[idx+1: strLen]
+			outLine = (*str)[idx+1 : strLen]
 		}
 		outTag = (*str)[1:idx]
 	} else {
@@ -243,106 +500,410 @@ func removeNot(str *string) *string {
 	return &outStr
 }
 
-func fixSeparator(str *string) *string {
-	outStr := path_sep_replacer_regex.ReplaceAllString(*str, pathSeparator)
+// Splits the pattern by "*" symbols, consecutive stars are processed as one.
+func splitByStars(str *string) []string {
+	list := strings.Split(*str, "*")
+	outList := []string{list[0]}
+	for _, v := range list[1 : len(list)-1] {
+		if len(v) != 0 {
+			outList = append(outList, v)
+		}
+	}
+	return append(outList, list[len(list)-1])
+}
+
+// Replaces all the separators with the canonical one, consecutive separators are replaced with one.
+func fixSeparator(str *string, options *Options) *string {
+	separators, separator := options.separators()
+	if !strings.ContainsAny(*str, separators) {
+		return str
+	}
+	var builder strings.Builder
+	builder.Grow(len(*str))
+	prevIsSeparator := false
+	for i := 0; i < len(*str); {
+		c, size := utf8.DecodeRuneInString((*str)[i:])
+		// the invalid bytes are never separators, they are copied as they are
+		isSeparator := (c != utf8.RuneError || size != 1) && strings.ContainsRune(separators, c)
+		if !isSeparator {
+			builder.WriteString((*str)[i : i+size])
+		} else if !prevIsSeparator {
+			builder.WriteString(separator)
+		}
+		prevIsSeparator = isSeparator
+		i += size
+	}
+	outStr := builder.String()
 	return &outStr
 }
 
-func prepareLine(line *string) (string, string, error) {
+// Cuts the comment which is separated from the pattern with 2 spaces or a tab.
+func cutComment(line string) (string, string) {
+	for i := 1; i < len(line); i++ {
+		if line[i] == commentStart[0] && (line[i-1] == '\t' || i > 1 && line[i-1] == ' ' && line[i-2] == ' ') {
+			return strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+		}
+	}
+	return line, ""
+}
+
+func (state *listState) prepareLine(line *string) (string, string, string, error) {
 	var err error = nil
 	outLine := strings.TrimSpace(*line)
 	outLine, tag, err := extractTag(&outLine)
 	outLine = strings.TrimSpace(outLine)
-	outLine = *fixSeparator(&outLine)
-	return outLine, tag, err
+	comment := ""
+	if state.inlineComments {
+		outLine, comment = cutComment(outLine)
+	}
+	outLine = normalize(outLine, state.normalizer)
+	outLine = *fixSeparator(&outLine, &state.options)
+	return outLine, tag, comment, err
 }
 
 /*********************************************************************************************************/
 ///////////////////////////////////////////////////////////////////////////////////////////////////////////
 /*********************************************************************************************************/
 
-func (ignoreList *List) combine(patternList1 *[]pattern, patternList2 *[]pattern) {
-	for i := range *patternList2 {
-		p2 := &(*patternList2)[i]
-		var found bool = false
-
-		for i := range *patternList1 {
-			p1 := &(*patternList1)[i]
+func (state *listState) combine(otherPatternList []pattern) {
+	// the patterns are moved in place, so the list must not share the array with the previous snapshot
+	patternList1 := append(make([]pattern, 0, len(state.patternList)+len(otherPatternList)), state.patternList...)
+	for i := range otherPatternList {
+		p2 := &otherPatternList[i]
 
-			if p1.prefix == p2.prefix && p1.suffix == p2.suffix {
-				*p1 = *p2
-				found = true
+		for i := range patternList1 {
+			if patternList1[i].isSame(p2) {
+				patternList1 = append(patternList1[:i], patternList1[i+1:]...)
 				break
 			}
 		}
-		if !found {
-			*patternList1 = append(*patternList1, *p2)
-		}
+		patternList1 = append(patternList1, *p2)
 	}
+	state.patternList = patternList1
 }
 
-func (ignoreList *List) hasMatchedPattern(filePath *string, patternList *[]pattern) (bool, int) {
-	fixedPath := fixSeparator(filePath)
-	for i := range *patternList {
-		p := &(*patternList)[i]
-		if p.IsEmpty() {
+// Processes the origin texts of the patterns again, it is used when the settings of the list are changed.
+// The pattern is kept as it is if its text can not be processed with the new settings.
+func (state *listState) reprocess() {
+	lineState := *state
+	patternList := make([]pattern, 0, len(state.patternList))
+	for i := range state.patternList {
+		p := &state.patternList[i]
+		lineState.dialect = p.dialect
+		lineState.patternList = nil
+		text := p.origin.Text
+		if err := lineState.processLine(&text, p.origin); err != nil {
+			patternList = append(patternList, *p)
 			continue
 		}
-		if p.isFile {
-			if p.prefix == *fixedPath {
-				return true, i
-			}
-		} else {
-			if p.HasPrefix() && p.HasSuffix() {
-				if strings.HasPrefix(*fixedPath, p.prefix) && strings.HasSuffix(*fixedPath, p.suffix) {
-					return true, i
-				}
-			} else if !p.HasPrefix() && p.HasSuffix() {
-				if strings.HasSuffix(*fixedPath, p.suffix) {
-					return true, i
-				}
-			} else if p.HasPrefix() && !p.HasSuffix() {
-				if strings.HasPrefix(*fixedPath, p.prefix) {
-					return true, i
+		for _, processed := range lineState.patternList {
+			processed.conditions = p.conditions
+			patternList = append(patternList, processed)
+		}
+	}
+	state.patternList = patternList
+}
+
+// The query is a file path prepared for matching.
+type query struct {
+	path     string
+	elements []string
+	isDir    bool
+	// the canonical separator of the path
+	separator string
+	// it is made on demand, see folded
+	foldedQuery *query
+}
+
+func newQuery(filePath *string, options *Options) (*query, error) {
+	fixedPath := fixSeparator(filePath, options)
+	_, separator := options.separators()
+	elements, changed, err := options.splitPath(*filePath, *fixedPath, separator)
+	if err != nil {
+		return nil, err
+	}
+	q := &query{path: *fixedPath, elements: elements, isDir: strings.HasSuffix(*fixedPath, separator), separator: separator}
+	if changed {
+		q.path = strings.Join(elements, separator)
+		if q.isDir && len(elements) != 0 {
+			q.path += separator
+		}
+		if len(options.BaseDir) == 0 && strings.HasPrefix(*fixedPath, separator) {
+			// the absolute path is kept absolute if there is no base folder
+			q.path = separator + q.path
+		}
+	}
+	return q, nil
+}
+
+// Returns the query for the path which is normalized, cleaned and split with the list settings.
+func (state *listState) query(filePath string) (*query, error) {
+	filePath = normalize(filePath, state.normalizer)
+	return newQuery(&filePath, &state.options)
+}
+
+// Returns the query for the path of the specified type, the trailing separator of the path does not matter.
+func (state *listState) typedQuery(filePath string, isDir bool) (*query, error) {
+	q, err := state.query(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return q.withType(isDir), nil
+}
+
+// Returns the query with the trailing separator for a folder and without it for a file.
+func (q *query) withType(isDir bool) *query {
+	if q.isDir == isDir || len(q.elements) == 0 {
+		return q
+	}
+	typed := &query{path: strings.TrimSuffix(q.path, q.separator), elements: q.elements, isDir: isDir, separator: q.separator}
+	if isDir {
+		typed.path += q.separator
+	}
+	return typed
+}
+
+// Returns the query for the parent folder with the specified number of the path elements.
+func (q *query) parent(elementsNum int) *query {
+	elements := q.elements[:elementsNum]
+	return &query{path: strings.Join(elements, q.separator) + q.separator, elements: elements, isDir: true,
+		separator: q.separator}
+}
+
+// Returns the path relative to the folder with the specified number of the path elements.
+func (q *query) relativePath(elementsNum int, separator string) string {
+	path := strings.Join(q.elements[elementsNum:], separator)
+	if q.isDir {
+		path += separator
+	}
+	return path
+}
+
+func (s *pattern) isMatched(q *query) bool {
+	q = s.queryFor(q)
+	if s.glob != nil {
+		return s.glob.isMatched(q.elements, q.isDir)
+	}
+	if s.IsEmpty() {
+		return false
+	}
+	if s.isFile {
+		return s.prefix == q.path
+	}
+	if len(s.segments) != 0 {
+		return matchSegments(q.path, s.segments)
+	}
+	if s.HasPrefix() && !strings.HasPrefix(q.path, s.prefix) {
+		return false
+	}
+	if s.HasSuffix() && !strings.HasSuffix(q.path, s.suffix) {
+		return false
+	}
+	return true
+}
+
+// Checks that the path starts with the first segment, ends with the last segment
+// and contains the other segments in the same order between them.
+func matchSegments(path string, segments []string) bool {
+	last := len(segments) - 1
+	if !strings.HasPrefix(path, segments[0]) {
+		return false
+	}
+	path = path[len(segments[0]):]
+	for _, segment := range segments[1:last] {
+		idx := strings.Index(path, segment)
+		if idx == -1 {
+			return false
+		}
+		path = path[idx+len(segment):]
+	}
+	return strings.HasSuffix(path, segments[last])
+}
+
+func (state *listState) hasMatchedPattern(q *query, include bool) (bool, int) {
+	for i := range state.patternList {
+		p := &state.patternList[i]
+		if p.include == include && p.isMatched(q) {
+			return true, i
+		}
+	}
+	return false, -1
+}
+
+// Returns index of the first matched include pattern
+// or index of the first matched exclude pattern if there is no matched include ones.
+func (state *listState) firstMatchedPattern(q *query) int {
+	if res, idx := state.hasMatchedPattern(q, true); res {
+		return idx
+	}
+	_, idx := state.hasMatchedPattern(q, false)
+	return idx
+}
+
+// Returns index of the last matched pattern.
+func (state *listState) lastMatchedPattern(q *query) int {
+	for i := len(state.patternList) - 1; i >= 0; i-- {
+		if state.patternList[i].isMatched(q) {
+			return i
+		}
+	}
+	return -1
+}
+
+// It returns true if the path is ignored and the tag of the pattern which decided.
+func (state *listState) isIgnored(q *query) (bool, string) {
+	idx := state.decisivePattern(q)
+	if idx == -1 {
+		return false, ""
+	}
+	p := &state.patternList[idx]
+	return !p.include, p.tag
+}
+
+// Returns index of the pattern which decides whether the path is ignored or -1.
+func (state *listState) decisivePattern(q *query) int {
+	if state.inherits() {
+		return inheritedPattern(state.patternList, state.actualEvaluation(), q, state.matchedInTree)
+	}
+	if state.actualEvaluation() == EvaluationIncludeFirst {
+		return state.firstMatchedPattern(q)
+	}
+	if state.dialect == DialectGitignore {
+		return state.lastMatchedPatternInTree(q)
+	}
+	return state.lastMatchedPattern(q)
+}
+
+// Returns index of the last matched pattern for the first ignored parent folder
+// or index of the last matched pattern for the path itself if its parents are not ignored.
+func (state *listState) lastMatchedPatternInTree(q *query) int {
+	for i := 1; i < len(q.elements); i++ {
+		idx := state.lastMatchedPattern(q.parent(i))
+		if idx != -1 && !state.patternList[idx].include {
+			return idx
+		}
+	}
+	return state.lastMatchedPattern(q)
+}
+
+// Replaces the patterns with the loaded ones.
+// The list becomes empty if an error is occurred, except the problems with the patterns in the lenient mode.
+// If keepOnError is true the list keeps the current patterns instead of becoming empty.
+func (ignoreList *List) load(reader io.Reader, fileName string, source includeSource, keepOnError bool) error {
+	var err error
+	ignoreList.update(func(state *listState) error {
+		state.patternList = nil
+		if err = state.load(reader, fileName, source); err != nil {
+			if _, ok := err.(ParseErrors); !ok {
+				if keepOnError {
+					return err
 				}
+				state.patternList = nil
 			}
 		}
+		return nil
+	})
+	return err
+}
+
+// The included files are opened with the source, see includeDirective.
+func (state *listState) load(reader io.Reader, fileName string, source includeSource) error {
+	l := &loader{state: state, source: source}
+	if len(fileName) != 0 {
+		l.files = append(l.files, source.resolve("", fileName))
 	}
-	return false, -1
+	if err := l.load(reader, fileName); err != nil {
+		return err
+	}
+	if len(l.parseErrors) != 0 {
+		return l.parseErrors
+	}
+	return nil
+}
+
+// It returns the first problem with the patterns or the included files in the strict mode,
+// in the lenient mode the problems are collected.
+func (l *loader) load(reader io.Reader, fileName string) error {
+	scanner := bufio.NewScanner(reader)
+	lineNum := 0
+	fileBlocks := len(l.blocks)
+	for scanner.Scan() {
+		line := scanner.Text()
+		lineNum++
+		origin := Origin{File: fileName, Line: lineNum, Text: line}
+		var err error
+		// the directives are the patterns in DialectGitignore
+		isDirective := false
+		if l.state.dialect == DialectNative {
+			if name, ok := parseInclude(line); ok {
+				isDirective, err = true, l.include(name, origin)
+			} else {
+				isDirective, err = l.processBlock(line, origin, fileBlocks)
+			}
+		}
+		if !isDirective {
+			patternsNum := len(l.state.patternList)
+			if parseErr := l.state.processLine(&line, origin); parseErr != nil {
+				err = parseErr
+			}
+			for i := patternsNum; i < len(l.state.patternList); i++ {
+				l.state.patternList[i].conditions = l.conditions
+			}
+		}
+		if parseErr, ok := err.(*ParseError); ok && l.state.lenient {
+			l.parseErrors = append(l.parseErrors, parseErr)
+		} else if err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return l.closeBlocks(fileBlocks)
 }
 
-func (ignoreList *List) processLine(inLine *string) error {
+func (state *listState) processLine(inLine *string, origin Origin) *ParseError {
 	if len(*inLine) == 0 {
 		return nil
 	}
 
-	line, tag, err := prepareLine(inLine)
+	if state.dialect == DialectGitignore {
+		return state.processGitignoreLine(inLine, origin)
+	}
+
+	text := strings.TrimSpace(*inLine)
+	if strings.HasPrefix(text, commentStart) {
+		return nil
+	}
+	if strings.HasPrefix(text, "\\"+commentStart) {
+		// the escaped "#" is not a separator
+		text = text[1:]
+	}
+
+	line, tag, comment, err := state.prepareLine(&text)
 	if err != nil {
-		return err
+		return newParseError(origin, strings.Index(*inLine, "[")+1, err)
 	}
 
-	var actualList *[]pattern
-	if strings.HasPrefix(line, not1) || strings.HasPrefix(line, not2) {
-		actualList = &ignoreList.includePatternList
-	} else {
-		actualList = &ignoreList.excludePatternList
+	include := strings.HasPrefix(line, not1) || strings.HasPrefix(line, not2)
+	line, caseInsensitive := state.extractCaseFlag(*removeNot(&line))
+	if caseInsensitive {
+		line = foldCase(line)
 	}
+	p := pattern{include: include, tag: tag, origin: origin, comment: comment, caseInsensitive: caseInsensitive}
 
-	indexLast := strings.LastIndex(line, "*")
-	if indexLast != -1 {
-		indexFirst := strings.Index(line, "*")
-		if indexLast != indexFirst {
-			return errors.New(fmt.Sprintf("too many <*> symbols in the pattern <%d>", line))
+	if strings.Contains(line, "*") {
+		list := splitByStars(&line)
+		p.prefix, p.suffix = list[0], list[len(list)-1]
+		if len(list) > 2 {
+			p.segments = list
 		}
-		list := strings.Split(line, "*")
-		*actualList = append(*actualList, pattern{prefix: *removeNot(&list[0]), suffix: list[1], isFile: false, tag: tag})
 	} else {
-		if strings.HasSuffix(line, pathSeparator) {
-			*actualList = append(*actualList, pattern{prefix: *removeNot(&line), isFile: false, tag: tag})
-		} else {
-			*actualList = append(*actualList, pattern{prefix: *removeNot(&line), isFile: true, tag: tag})
-		}
+		p.prefix = line
+		_, separator := state.options.separators()
+		p.isFile = !strings.HasSuffix(line, separator)
 	}
+	state.patternList = append(state.patternList, p)
 	return nil
 }
 
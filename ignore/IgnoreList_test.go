@@ -30,8 +30,10 @@
 package ignore
 
 import (
+	"errors"
 	"github.com/stretchr/testify/assert"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -544,42 +546,85 @@ func TestPathFolderFileName_case2(t *testing.T) {
 ///////////////////////////////////////////////////////////////////////////////////////////////////////////
 /*********************************************************************************************************/
 
-func TestIncorrectPattern_case1(t *testing.T) {
+func TestMultipleStars_case1(t *testing.T) {
 	a := assert.New(t)
 	writeIgnoreListFile([]string{"folder1*/file*1", "folde*r2/folder1/*fil*e1"})
 	ignoreList, err := NewListFromFile(filePath)
-	a.Error(err)
+	a.NoError(err)
 	fpList := filePathList()
-	a.False(ignoreList.IsIgnored(fpList[0])) // "folder1/file1"
+	a.True(ignoreList.IsIgnored(fpList[0]))  // "folder1/file1"
 	a.False(ignoreList.IsIgnored(fpList[1])) // "folder1/file2"
-	a.False(ignoreList.IsIgnored(fpList[2])) // "folder2/folder1/file1"
+	a.True(ignoreList.IsIgnored(fpList[2]))  // "folder2/folder1/file1"
 	a.False(ignoreList.IsIgnored(fpList[3])) // "folder2/folder2/file1"
 	removeIgnoreListFile()
 }
 
-func TestIncorrectPattern_case2(t *testing.T) {
+func TestMultipleStars_case2(t *testing.T) {
 	a := assert.New(t)
 	ignoreList := NewList()
-	err := ignoreList.AddPattern("folder1*/file*1")
-	a.Error(err)
-	err = ignoreList.AddPattern("folde*r2/folder1/*fil*e1")
-	a.Error(err)
-	fpList := filePathList()
-	a.False(ignoreList.IsIgnored(fpList[0])) // "folder1/file1"
-	a.False(ignoreList.IsIgnored(fpList[1])) // "folder1/file2"
-	a.False(ignoreList.IsIgnored(fpList[2])) // "folder2/folder1/file1"
-	a.False(ignoreList.IsIgnored(fpList[3])) // "folder2/folder2/file1"
+	err := ignoreList.AddPattern("build/*/cache/*.tmp")
+	a.NoError(err)
+	a.True(ignoreList.IsIgnored("build/debug/cache/1.tmp"))
+	a.True(ignoreList.IsIgnored("build/debug/x64/cache/sub/1.tmp"))
+	a.False(ignoreList.IsIgnored("build/debug/cache/1.tmp2"))
+	a.False(ignoreList.IsIgnored("build/debug/1.tmp"))
+	a.False(ignoreList.IsIgnored("build/cache/1.tmp"))
 }
 
-func TestIncorrectPattern_case3(t *testing.T) {
+func TestMultipleStars_case3(t *testing.T) {
 	a := assert.New(t)
-	writeIgnoreListFile([]string{"prefix1*/prefix2*", ""})
-	_, err := NewListFromFile(filePath)
+	ignoreList := NewList()
+	err := ignoreList.AddPattern("*test*")
+	a.NoError(err)
+	err = ignoreList.AddPattern("not *test*.go")
+	a.NoError(err)
+	a.True(ignoreList.IsIgnored("test"))
+	a.True(ignoreList.IsIgnored("folder1/my-test-file"))
+	a.False(ignoreList.IsIgnored("folder1/my-test.go"))
+	a.False(ignoreList.IsIgnored("folder1/file1"))
+}
+
+// The segments must not overlap
+func TestMultipleStars_case4(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := NewList()
+	err := ignoreList.AddPattern("ab*b*ba")
+	a.NoError(err)
+	a.True(ignoreList.IsIgnored("abbba"))
+	a.False(ignoreList.IsIgnored("abba"))
+	a.False(ignoreList.IsIgnored("aba"))
+}
+
+func TestMultipleStars_case5(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := NewList()
+	err := ignoreList.AddPattern("prefix**suffix")
+	a.NoError(err)
+	if a.Len(ignoreList.snapshot().patternList, 1) {
+		a.Equal(pattern{prefix: "prefix", suffix: "suffix", isFile: false, origin: Origin{Text: "prefix**suffix"}}, ignoreList.snapshot().patternList[0])
+	}
+	err = ignoreList.AddPattern("prefix1*/prefix2*")
+	a.NoError(err)
+	if a.Len(ignoreList.snapshot().patternList, 2) {
+		a.Equal([]string{"prefix1", pathSeparator + "prefix2", ""}, ignoreList.snapshot().patternList[1].segments)
+	}
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func TestIncorrectPattern_case1(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := NewList()
+	err := ignoreList.AddPattern("[folder1/*")
 	a.Error(err)
-	removeIgnoreListFile()
+	fpList := filePathList()
+	a.False(ignoreList.IsIgnored(fpList[0])) // "folder1/file1"
+	a.False(ignoreList.IsIgnored(fpList[1])) // "folder1/file2"
 }
 
-func TestIncorrectPattern_case4(t *testing.T) {
+func TestIncorrectPattern_case2(t *testing.T) {
 	a := assert.New(t)
 	writeIgnoreListFile([]string{"[prefix1*", ""})
 	_, err := NewListFromFile(filePath)
@@ -606,13 +651,13 @@ func TestCombine_case1(t *testing.T) {
 	ignoreList2.AddPattern("[tag4] test3/*test3")
 
 	ignoreList1.Combine(&ignoreList2)
-	if a.Len(ignoreList1.excludePatternList, 6) {
-		a.Equal(pattern{tag: "tag0", prefix: "A" + ps, suffix: "A", isFile: false}, ignoreList1.excludePatternList[0])
-		a.Equal(pattern{tag: "tag1", prefix: "test1" + ps, suffix: "test2", isFile: false}, ignoreList1.excludePatternList[1])
-		a.Equal(pattern{tag: "tag2", prefix: "test3" + ps, suffix: "test3" + ps, isFile: false}, ignoreList1.excludePatternList[2])
-		a.Equal(pattern{tag: "tag0", prefix: "B" + ps, suffix: "B", isFile: false}, ignoreList1.excludePatternList[3])
-		a.Equal(pattern{tag: "tag3", prefix: "test1" + ps, suffix: "test2" + ps, isFile: false}, ignoreList1.excludePatternList[4])
-		a.Equal(pattern{tag: "tag4", prefix: "test3" + ps, suffix: "test3", isFile: false}, ignoreList1.excludePatternList[5])
+	if a.Len(ignoreList1.snapshot().patternList, 6) {
+		a.Equal(pattern{tag: "tag0", prefix: "A" + ps, suffix: "A", isFile: false, origin: Origin{Text: "[tag0] A/*A"}}, ignoreList1.snapshot().patternList[0])
+		a.Equal(pattern{tag: "tag1", prefix: "test1" + ps, suffix: "test2", isFile: false, origin: Origin{Text: "[tag1] test1/*test2"}}, ignoreList1.snapshot().patternList[1])
+		a.Equal(pattern{tag: "tag2", prefix: "test3" + ps, suffix: "test3" + ps, isFile: false, origin: Origin{Text: "[tag2] test3/*test3/"}}, ignoreList1.snapshot().patternList[2])
+		a.Equal(pattern{tag: "tag0", prefix: "B" + ps, suffix: "B", isFile: false, origin: Origin{Text: "[tag0] B/*B"}}, ignoreList1.snapshot().patternList[3])
+		a.Equal(pattern{tag: "tag3", prefix: "test1" + ps, suffix: "test2" + ps, isFile: false, origin: Origin{Text: "[tag3] test1/*test2/"}}, ignoreList1.snapshot().patternList[4])
+		a.Equal(pattern{tag: "tag4", prefix: "test3" + ps, suffix: "test3", isFile: false, origin: Origin{Text: "[tag4] test3/*test3"}}, ignoreList1.snapshot().patternList[5])
 	}
 }
 
@@ -631,11 +676,12 @@ func TestCombine_case2(t *testing.T) {
 	ignoreList2.AddPattern("[tag4] test3/*test3")
 
 	ignoreList1.Combine(&ignoreList2)
-	if a.Len(ignoreList1.excludePatternList, 4) {
-		a.Equal(pattern{tag: "tag0", prefix: "A" + ps, suffix: "A", isFile: false}, ignoreList1.excludePatternList[0])
-		a.Equal(pattern{tag: "tag3", prefix: "test1" + ps, suffix: "test2" + ps, isFile: false}, ignoreList1.excludePatternList[1])
-		a.Equal(pattern{tag: "tag4", prefix: "test3" + ps, suffix: "test3", isFile: false}, ignoreList1.excludePatternList[2])
-		a.Equal(pattern{tag: "tag0", prefix: "B" + ps, suffix: "B", isFile: false}, ignoreList1.excludePatternList[3])
+	// the replaced patterns are moved to the end of the list
+	if a.Len(ignoreList1.snapshot().patternList, 4) {
+		a.Equal(pattern{tag: "tag0", prefix: "A" + ps, suffix: "A", isFile: false, origin: Origin{Text: "[tag0] A/*A"}}, ignoreList1.snapshot().patternList[0])
+		a.Equal(pattern{tag: "tag0", prefix: "B" + ps, suffix: "B", isFile: false, origin: Origin{Text: "[tag0] B/*B"}}, ignoreList1.snapshot().patternList[1])
+		a.Equal(pattern{tag: "tag3", prefix: "test1" + ps, suffix: "test2" + ps, isFile: false, origin: Origin{Text: "[tag3] test1/*test2/"}}, ignoreList1.snapshot().patternList[2])
+		a.Equal(pattern{tag: "tag4", prefix: "test3" + ps, suffix: "test3", isFile: false, origin: Origin{Text: "[tag4] test3/*test3"}}, ignoreList1.snapshot().patternList[3])
 	}
 }
 
@@ -855,3 +901,239 @@ func TestTagWhenInclude_case2(t *testing.T) {
 /*********************************************************************************************************/
 ///////////////////////////////////////////////////////////////////////////////////////////////////////////
 /*********************************************************************************************************/
+
+func TestEvaluationLastMatch_case1(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := NewList()
+	a.Equal(EvaluationIncludeFirst, ignoreList.Evaluation())
+	ignoreList.SetEvaluation(EvaluationLastMatch)
+	a.Equal(EvaluationLastMatch, ignoreList.Evaluation())
+
+	a.NoError(ignoreList.AddPattern("[tag1] folder2/*"))
+	a.NoError(ignoreList.AddPattern("[tag2] not folder2/folder1/*"))
+	a.NoError(ignoreList.AddPattern("[tag3] folder2/folder1/file1"))
+	fpList := filePathList()
+
+	res, tag := ignoreList.IsIgnoredEx(fpList[2]) // "folder2/folder1/file1"
+	a.True(res)
+	a.Equal("tag3", tag)
+
+	res, tag = ignoreList.IsIgnoredEx("folder2/folder1/file2")
+	a.False(res)
+	a.Equal("tag2", tag)
+
+	res, tag = ignoreList.IsIgnoredEx(fpList[3]) // "folder2/folder2/file1"
+	a.True(res)
+	a.Equal("tag1", tag)
+
+	// the same patterns with the default evaluation
+	ignoreList.SetEvaluation(EvaluationDefault)
+	a.False(ignoreList.IsIgnored(fpList[2]))
+}
+
+func TestEvaluationLastMatch_case2(t *testing.T) {
+	a := assert.New(t)
+	ignoreList1 := NewList()
+	ignoreList1.SetEvaluation(EvaluationLastMatch)
+	ignoreList1.AddPattern("folder2/*")
+	ignoreList1.AddPattern("not folder2/folder1/*")
+	ignoreList2 := NewList()
+	ignoreList2.AddPattern("[tag] folder2/*")
+
+	ignoreList1.Combine(ignoreList2)
+	if a.Len(ignoreList1.snapshot().patternList, 2) {
+		a.Equal("tag", ignoreList1.snapshot().patternList[1].tag)
+	}
+	a.True(ignoreList1.IsIgnored("folder2/folder1/file1"))
+}
+
+func TestEvaluationLastMatch_case3(t *testing.T) {
+	a := assert.New(t)
+	ignoreList1 := NewList()
+	ignoreList1.SetEvaluation(EvaluationLastMatch)
+	ignoreList1.AddPattern("folder2/*")
+	ignoreList1.AddPattern("not folder2/folder1/file1")
+	ignoreList2 := NewList()
+	ignoreList2.AddPattern("folder2/folder1/file1")
+
+	ignoreList1.Combine(ignoreList2)
+	a.Len(ignoreList1.snapshot().patternList, 3)
+	a.True(ignoreList1.IsIgnored("folder2/folder1/file1"))
+}
+
+// The evaluation can be changed after combining
+func TestEvaluationLastMatch_case4(t *testing.T) {
+	a := assert.New(t)
+	ignoreList1 := NewList()
+	ignoreList1.AddPattern("folder2/*")
+	ignoreList1.AddPattern("not folder2/folder1/*")
+	ignoreList2 := NewList()
+	ignoreList2.AddPattern("[tag] folder2/*")
+
+	ignoreList1.Combine(ignoreList2)
+	res, tag := ignoreList1.IsIgnoredEx("folder2/file1")
+	a.True(res)
+	a.Equal("tag", tag)
+	a.False(ignoreList1.IsIgnored("folder2/folder1/file1"))
+
+	ignoreList1.SetEvaluation(EvaluationLastMatch)
+	a.True(ignoreList1.IsIgnored("folder2/folder1/file1"))
+}
+
+func TestEvaluationIncludeFirst_gitignore(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := NewListWithDialect(DialectGitignore)
+	a.Equal(EvaluationLastMatch, ignoreList.Evaluation())
+	ignoreList.SetEvaluation(EvaluationIncludeFirst)
+	ignoreList.AddPattern("!file1")
+	ignoreList.AddPattern("file1")
+	a.False(ignoreList.IsIgnored("file1"))
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func TestLoadFromReader(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := NewList()
+	ignoreList.AddPattern("folder1/*")
+	err := ignoreList.LoadFromReader(strings.NewReader("folder2/*\r\n\nnot folder2/folder1/*"))
+	a.NoError(err)
+	fpList := filePathList()
+	a.False(ignoreList.IsIgnored(fpList[0])) // "folder1/file1"
+	a.False(ignoreList.IsIgnored(fpList[1])) // "folder1/file2"
+	a.False(ignoreList.IsIgnored(fpList[2])) // "folder2/folder1/file1"
+	a.True(ignoreList.IsIgnored(fpList[3]))  // "folder2/folder2/file1"
+
+	match := ignoreList.Explain(fpList[2])
+	if a.NotNil(match.Rule) {
+		a.Equal(Origin{Line: 3, Text: "not folder2/folder1/*"}, match.Rule.Origin)
+	}
+}
+
+func TestNewListFromString(t *testing.T) {
+	a := assert.New(t)
+	ignoreList, err := NewListFromString("folder1/*\n[tag\nfolder2/*")
+	var parseErr *ParseError
+	if a.True(errors.As(err, &parseErr)) {
+		a.Equal(2, parseErr.Line)
+		a.Equal("2:1: "+ErrTagNotClosed.Error()+" <[tag>", parseErr.Error())
+	}
+	a.Empty(ignoreList.Rules())
+
+	ignoreList, err = NewListFromString("folder1/*\nfolder2/*")
+	a.NoError(err)
+	for _, value := range filePathList() {
+		a.True(ignoreList.IsIgnored(value))
+	}
+}
+
+func TestNewListFromLines(t *testing.T) {
+	a := assert.New(t)
+	ignoreList, err := NewListFromLines([]string{"[tag] folder1/*", "", "not folder1/file1"})
+	a.NoError(err)
+	rules := ignoreList.Rules()
+	if a.Len(rules, 2) {
+		a.Equal(Origin{Line: 1, Text: "[tag] folder1/*"}, rules[0].Origin)
+		a.Equal(Origin{Line: 3, Text: "not folder1/file1"}, rules[1].Origin)
+	}
+	a.False(ignoreList.IsIgnored("folder1/file1"))
+	a.True(ignoreList.IsIgnored("folder1/file2"))
+
+	ignoreList, err = NewListFromLines(nil)
+	a.NoError(err)
+	a.Empty(ignoreList.Rules())
+}
+
+func TestIsIgnoredDir(t *testing.T) {
+	a := assert.New(t)
+	ignoreList, err := NewListFromLines([]string{"build/", "folder1/file1", "not build/keep/"})
+	a.NoError(err)
+
+	a.True(ignoreList.IsIgnoredDir("build"))
+	a.True(ignoreList.IsIgnoredDir("build/"))
+	a.True(ignoreList.IsIgnored("build/"))
+	a.True(ignoreList.IsIgnored("build/file1"))
+	// the file with the same name is not ignored
+	a.False(ignoreList.IsIgnored("build"))
+	a.False(ignoreList.IsIgnoredDir("folder2/build"))
+	a.False(ignoreList.IsIgnoredDir("build/keep"))
+
+	// the file patterns do not match the folders
+	a.True(ignoreList.IsIgnored("folder1/file1"))
+	a.False(ignoreList.IsIgnoredDir("folder1/file1"))
+
+	matcher := ignoreList.Compile()
+	a.True(matcher.IsIgnoredDir("build"))
+	a.False(matcher.IsIgnored("build"))
+	a.False(matcher.IsIgnoredDir("folder1/file1"))
+
+	tree := NewTree(".ignore")
+	tree.SetList("folder2", ignoreList)
+	a.True(tree.IsIgnoredDir("folder2/build"))
+	a.False(tree.IsIgnored("folder2/build"))
+}
+
+func TestComments(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := NewList()
+	ignoreList.SetInlineComments(true)
+	a.NoError(ignoreList.LoadFromReader(strings.NewReader(strings.Join([]string{
+		"# generated by tool X",
+		"   # indented comment",
+		"\\#file1",
+		"[tag] folder1/*  # build results",
+		"not folder1/file#1\t# keep it",
+		"folder2/*#1",
+		"folder3/* #1",
+		"#",
+	}, "\n"))))
+	rules := ignoreList.Rules()
+	if a.Len(rules, 5) {
+		a.Equal("#file1", rules[0].Pattern)
+		a.Equal("", rules[0].Comment)
+		a.Equal("build results", rules[1].Comment)
+		a.Equal("tag", rules[1].Tag)
+		a.Equal("folder1"+pathSeparator+"file#1", rules[2].Pattern)
+		a.Equal("keep it", rules[2].Comment)
+		a.Equal("folder2"+pathSeparator+"*#1", rules[3].Pattern)
+		a.Equal("folder3"+pathSeparator+"* #1", rules[4].Pattern)
+	}
+	a.False(ignoreList.IsIgnored("# generated by tool X"))
+	a.True(ignoreList.IsIgnored("#file1"))
+	a.True(ignoreList.IsIgnored("folder1/file1"))
+	a.False(ignoreList.IsIgnored("folder1/file#1"))
+	a.True(ignoreList.IsIgnored("folder2/file#1"))
+
+	match := ignoreList.Explain("folder1/file2")
+	if a.NotNil(match.Rule) {
+		a.Equal("build results", match.Rule.Comment)
+	}
+}
+
+func TestComments_default(t *testing.T) {
+	a := assert.New(t)
+	ignoreList, err := NewListFromLines([]string{"# comment", "My Files #2/*", "folder1/*  # 1", "not folder1/file\t#1"})
+	a.NoError(err)
+	// the "#" inside the patterns is not a comment
+	a.Equal([]string{"My Files #2" + pathSeparator + "*", "folder1" + pathSeparator + "*  # 1",
+		"folder1" + pathSeparator + "file\t#1"}, rulePatterns(ignoreList.Rules()))
+	for _, rule := range ignoreList.Rules() {
+		a.Empty(rule.Comment)
+	}
+	a.True(ignoreList.IsIgnored("My Files #2/file1"))
+	a.False(ignoreList.IsIgnored("My Files/file1"))
+	a.False(ignoreList.IsIgnored("folder1/file1"))
+
+	// the existing patterns are processed again when the mode is changed
+	ignoreList.SetInlineComments(true)
+	a.Equal([]string{"My Files #2" + pathSeparator + "*", "folder1" + pathSeparator + "*",
+		"folder1" + pathSeparator + "file"}, rulePatterns(ignoreList.Rules()))
+	a.True(ignoreList.IsIgnored("folder1/file1"))
+	a.False(ignoreList.IsIgnored("folder1/file"))
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
@@ -0,0 +1,157 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// The directive which loads the patterns of other files in place of the directive line, see List.
+const includeDirective = "@include "
+
+// The maximum number of the nested includes.
+const maxIncludeDepth = 16
+
+// The source of the included files.
+type includeSource interface {
+	open(name string) (io.ReadCloser, error)
+	glob(pattern string) ([]string, error)
+	// Returns the path of the included file relative to the file which includes it.
+	resolve(from string, name string) string
+}
+
+type osSource struct{}
+
+func (osSource) open(name string) (io.ReadCloser, error) {
+	return os.Open(name)
+}
+
+func (osSource) glob(pattern string) ([]string, error) {
+	return filepath.Glob(pattern)
+}
+
+func (osSource) resolve(from string, name string) string {
+	if filepath.IsAbs(name) {
+		return filepath.Clean(name)
+	}
+	return filepath.Join(filepath.Dir(from), name)
+}
+
+type fsSource struct {
+	fsys fs.FS
+}
+
+func (s fsSource) open(name string) (io.ReadCloser, error) {
+	return s.fsys.Open(name)
+}
+
+func (s fsSource) glob(pattern string) ([]string, error) {
+	return fs.Glob(s.fsys, pattern)
+}
+
+func (s fsSource) resolve(from string, name string) string {
+	return path.Join(path.Dir(from), name)
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// The loader loads the patterns of a file and of the files which it includes.
+type loader struct {
+	state  *listState
+	source includeSource
+	// the files which are being loaded, the last one is the current file
+	files       []string
+	parseErrors ParseErrors
+	// the blocks which contain the current line and their conditions
+	blocks     []block
+	conditions []string
+}
+
+// Returns the path of the included file or pattern if the line is the include directive.
+func parseInclude(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, includeDirective) {
+		return "", false
+	}
+	return strings.TrimSpace(line[len(includeDirective):]), true
+}
+
+// Loads the files of the include directive.
+func (l *loader) include(name string, origin Origin) error {
+	column := strings.Index(origin.Text, name) + 1
+	if len(l.files) > maxIncludeDepth {
+		return newParseError(origin, column, ErrIncludeDepth)
+	}
+	filePath := l.source.resolve(origin.File, name)
+	if !strings.ContainsAny(name, "*?[") {
+		return l.includeFile(filePath, origin, column)
+	}
+	matches, err := l.source.glob(filePath)
+	if err != nil {
+		return newParseError(origin, column, err)
+	}
+	for _, match := range matches {
+		if err := l.includeFile(match, origin, column); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *loader) includeFile(filePath string, origin Origin, column int) error {
+	for _, f := range l.files {
+		if f == filePath {
+			return newParseError(origin, column, ErrIncludeCycle)
+		}
+	}
+	file, err := l.source.open(filePath)
+	if err != nil {
+		return newParseError(origin, column, err)
+	}
+	defer file.Close()
+	l.files = append(l.files, filePath)
+	defer func() { l.files = l.files[:len(l.files)-1] }()
+	return l.load(file, filePath)
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
@@ -0,0 +1,191 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func includeTestFS() fstest.MapFS {
+	return fstest.MapFS{
+		".ignore":                {Data: []byte("folder1/*\n@include rules/base.ignore\n@include rules.d/*.ignore\nnot folder1/file1\n")},
+		"rules/base.ignore":      {Data: []byte("[base] *.tmp\n  @include  ../rules.d/1.ignore  \n")},
+		"rules.d/1.ignore":       {Data: []byte("*.log\n")},
+		"rules.d/2.ignore":       {Data: []byte("build/\n")},
+		"rules.d/readme.md":      {Data: []byte("readme\n")},
+		"cycle/a.ignore":         {Data: []byte("a\n@include b.ignore\n")},
+		"cycle/b.ignore":         {Data: []byte("b\n@include ./a.ignore\n")},
+		"cycle/self.ignore":      {Data: []byte("@include self.ignore\n")},
+		"missing/.ignore":        {Data: []byte("folder1/*\n@include none.ignore\n@include none.d/*.ignore\nfolder2/*\n")},
+		"invalid/.ignore":        {Data: []byte("folder1/*\n@include invalid.ignore\n")},
+		"invalid/invalid.ignore": {Data: []byte("folder2/*\n[tag\n")},
+	}
+}
+
+func ruleFiles(ignoreList *List) []string {
+	var files []string
+	for _, rule := range ignoreList.Rules() {
+		files = append(files, fmt.Sprintf("%s:%d %s", rule.Origin.File, rule.Origin.Line, rule.Origin.Text))
+	}
+	return files
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func TestInclude(t *testing.T) {
+	a := assert.New(t)
+	ignoreList, err := NewListFromFS(includeTestFS(), ".ignore")
+	a.NoError(err)
+	a.Equal([]string{
+		".ignore:1 folder1/*",
+		"rules/base.ignore:1 [base] *.tmp",
+		"rules.d/1.ignore:1 *.log",
+		"rules.d/1.ignore:1 *.log",
+		"rules.d/2.ignore:1 build/",
+		".ignore:4 not folder1/file1",
+	}, ruleFiles(ignoreList))
+
+	a.True(ignoreList.IsIgnored("folder2/a.log"))
+	a.True(ignoreList.IsIgnored("build/file1"))
+	a.False(ignoreList.IsIgnored("readme"))
+	match := ignoreList.Explain("folder2/a.tmp")
+	if a.NotNil(match.Rule) {
+		a.Equal(Origin{File: "rules/base.ignore", Line: 1, Text: "[base] *.tmp"}, match.Rule.Origin)
+	}
+}
+
+func TestInclude_cycle(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := NewList()
+	err := ignoreList.LoadFromFS(includeTestFS(), "cycle/a.ignore")
+	a.ErrorIs(err, ErrIncludeCycle)
+	var parseErr *ParseError
+	if a.ErrorAs(err, &parseErr) {
+		a.Equal("cycle/b.ignore", parseErr.File)
+		a.Equal(2, parseErr.Line)
+		a.Equal(10, parseErr.Column)
+	}
+	a.Empty(ignoreList.Rules())
+
+	err = ignoreList.LoadFromFS(includeTestFS(), "cycle/self.ignore")
+	a.ErrorIs(err, ErrIncludeCycle)
+
+	// the same file can be included several times if it does not include itself
+	ignoreList.SetLenient(true)
+	a.ErrorIs(ignoreList.LoadFromFS(includeTestFS(), "cycle/a.ignore"), ErrIncludeCycle)
+	a.Equal([]string{"cycle/a.ignore:1 a", "cycle/b.ignore:1 b"}, ruleFiles(ignoreList))
+}
+
+func TestInclude_depth(t *testing.T) {
+	a := assert.New(t)
+	fsys := fstest.MapFS{}
+	for i := 0; i < 20; i++ {
+		fsys[fmt.Sprintf("%d.ignore", i)] = &fstest.MapFile{Data: []byte(fmt.Sprintf("file%d\n@include %d.ignore\n", i, i+1))}
+	}
+	ignoreList := NewList()
+	err := ignoreList.LoadFromFS(fsys, "0.ignore")
+	a.ErrorIs(err, ErrIncludeDepth)
+	var parseErr *ParseError
+	if a.ErrorAs(err, &parseErr) {
+		a.Equal(fmt.Sprintf("%d.ignore", maxIncludeDepth), parseErr.File)
+	}
+}
+
+func TestInclude_errors(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := NewList()
+	err := ignoreList.LoadFromFS(includeTestFS(), "missing/.ignore")
+	a.ErrorIs(err, fs.ErrNotExist)
+	var parseErr *ParseError
+	if a.ErrorAs(err, &parseErr) {
+		a.Equal(Origin{File: "missing/.ignore", Line: 2, Text: "@include none.ignore"},
+			Origin{File: parseErr.File, Line: parseErr.Line, Text: parseErr.Text})
+	}
+	a.Empty(ignoreList.Rules())
+
+	err = ignoreList.LoadFromFS(includeTestFS(), "invalid/.ignore")
+	a.ErrorIs(err, ErrTagNotClosed)
+	if a.ErrorAs(err, &parseErr) {
+		a.Equal("invalid/invalid.ignore", parseErr.File)
+		a.Equal(2, parseErr.Line)
+	}
+
+	ignoreList.SetLenient(true)
+	err = ignoreList.LoadFromFS(includeTestFS(), "missing/.ignore")
+	var parseErrors ParseErrors
+	if a.ErrorAs(err, &parseErrors) {
+		a.Len(parseErrors, 1)
+	}
+	a.Equal([]string{"missing/.ignore:1 folder1/*", "missing/.ignore:4 folder2/*"}, ruleFiles(ignoreList))
+}
+
+func TestInclude_file(t *testing.T) {
+	a := assert.New(t)
+	dir := t.TempDir()
+	a.NoError(os.MkdirAll(filepath.Join(dir, "rules"), 0755))
+	a.NoError(os.WriteFile(filepath.Join(dir, ".ignore"), []byte("@include rules/*.ignore\n"), 0644))
+	a.NoError(os.WriteFile(filepath.Join(dir, "rules", "1.ignore"), []byte("@include ../common.ignore\n"), 0644))
+	a.NoError(os.WriteFile(filepath.Join(dir, "common.ignore"), []byte("*.tmp\n"), 0644))
+
+	ignoreList, err := NewListFromFile(filepath.Join(dir, ".ignore"))
+	a.NoError(err)
+	a.Equal([]string{filepath.Join(dir, "common.ignore") + ":1 *.tmp"}, ruleFiles(ignoreList))
+	a.True(ignoreList.IsIgnored("a.tmp"))
+
+	// the patterns of AddPattern are not directives
+	ignoreList.AddPattern("@include common.ignore")
+	a.True(ignoreList.IsIgnored("@include common.ignore"))
+	a.Equal("*.tmp\n[] @include common.ignore\n", ignoreList.String())
+}
+
+func TestInclude_gitignore(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := NewListWithDialect(DialectGitignore)
+	a.NoError(ignoreList.LoadFromFS(includeTestFS(), "rules/base.ignore"))
+	a.Equal([]string{"rules/base.ignore:1 [base] *.tmp", "rules/base.ignore:2   @include  ../rules.d/1.ignore  "},
+		ruleFiles(ignoreList))
+	a.False(ignoreList.IsIgnored("a.log"))
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
@@ -0,0 +1,139 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"strings"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// Sets the mode in which a path inherits the ignoring from its parent folders like git does.
+//
+// In this mode the parent folders of a path are checked from the root one by one as folders.
+// If a parent folder is ignored then the pattern which ignored it is processed as if it matched the path too,
+// so the path is ignored unless a pattern which includes it wins by the list evaluation:
+// with EvaluationIncludeFirst any include pattern which matches the path re-includes it,
+// with EvaluationLastMatch only the include patterns which are after the pattern which ignored the parent.
+//
+// The file patterns like "some-folder/file" also match the folders with the same path in this mode,
+// so the pattern "some-folder" ignores all the children of the folder "some-folder".
+//
+// The lists with DialectGitignore always use the git rules, see DialectGitignore.
+func (ignoreList *List) SetInheritance(inheritance bool) {
+	ignoreList.update(func(state *listState) error {
+		state.inheritance = inheritance
+		return nil
+	})
+}
+
+// Returns true if the list uses the inheritance mode, DialectGitignore does not use it.
+func (state *listState) inherits() bool {
+	return state.inheritance && state.dialect == DialectNative
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// Returns true if the pattern matches the path or the pattern is a file pattern and the path is the same folder.
+func (s *pattern) isMatchedInTree(q *query) bool {
+	if s.isMatched(q) {
+		return true
+	}
+	if s.glob != nil || !s.isFile || !q.isDir {
+		return false
+	}
+	return s.prefix == strings.TrimSuffix(s.queryFor(q).path, q.separator)
+}
+
+// Returns sorted indices of the patterns which match the path in the inheritance mode.
+func (state *listState) matchedInTree(q *query) []int {
+	var matched []int
+	for i := range state.patternList {
+		if state.patternList[i].isMatchedInTree(q) {
+			matched = append(matched, i)
+		}
+	}
+	return matched
+}
+
+// Returns index of the pattern which decides whether the path is ignored in the inheritance mode or -1.
+// The matched function returns sorted indices of the patterns which match the query.
+func inheritedPattern(patternList []pattern, evaluation Evaluation, q *query, matched func(q *query) []int) int {
+	var inherited []int
+	for i := 1; i < len(q.elements); i++ {
+		idx := decisiveOf(patternList, evaluation, mergeIndices(matched(q.parent(i)), inherited))
+		if idx != -1 && !patternList[idx].include {
+			inherited = mergeIndices(inherited, []int{idx})
+		}
+	}
+	return decisiveOf(patternList, evaluation, mergeIndices(matched(q), inherited))
+}
+
+func decisiveOf(patternList []pattern, evaluation Evaluation, matched []int) int {
+	if evaluation == EvaluationIncludeFirst {
+		return decisiveIncludeFirst(patternList, matched)
+	}
+	return decisiveLastMatch(matched)
+}
+
+// Returns sorted union of the sorted indices.
+func mergeIndices(list1 []int, list2 []int) []int {
+	if len(list2) == 0 {
+		return list1
+	}
+	if len(list1) == 0 {
+		return list2
+	}
+	out := make([]int, 0, len(list1)+len(list2))
+	for len(list1) != 0 && len(list2) != 0 {
+		switch {
+		case list1[0] < list2[0]:
+			out = append(out, list1[0])
+			list1 = list1[1:]
+		case list1[0] > list2[0]:
+			out = append(out, list2[0])
+			list2 = list2[1:]
+		default:
+			out = append(out, list1[0])
+			list1 = list1[1:]
+			list2 = list2[1:]
+		}
+	}
+	out = append(out, list1...)
+	return append(out, list2...)
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
@@ -0,0 +1,168 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"testing/fstest"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func newInheritanceList(a *assert.Assertions, evaluation Evaluation, lines ...string) *List {
+	ignoreList, err := NewListFromLines(lines)
+	a.NoError(err)
+	ignoreList.SetEvaluation(evaluation)
+	ignoreList.SetInheritance(true)
+	return ignoreList
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func TestInheritance_filePattern(t *testing.T) {
+	a := assert.New(t)
+	ignoreList, err := NewListFromLines([]string{"some-folder", "folder1/file1"})
+	a.NoError(err)
+	a.False(ignoreList.IsIgnored("some-folder/file1"))
+	a.False(ignoreList.IsIgnoredDir("some-folder"))
+
+	ignoreList.SetInheritance(true)
+	a.True(ignoreList.IsIgnored("some-folder"))
+	a.True(ignoreList.IsIgnoredDir("some-folder"))
+	a.True(ignoreList.IsIgnored("some-folder/file1"))
+	a.True(ignoreList.IsIgnored("some-folder/folder1/file1"))
+	a.True(ignoreList.IsIgnored("folder1/file1/file2"))
+	a.False(ignoreList.IsIgnored("folder1/file2"))
+	a.False(ignoreList.IsIgnored("folder2/some-folder/file1"))
+}
+
+func TestInheritance_folderPattern(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := newInheritanceList(a, EvaluationDefault, "[tag] some-folder/")
+	a.False(ignoreList.IsIgnored("some-folder"))
+	a.True(ignoreList.IsIgnoredDir("some-folder"))
+	res, tag := ignoreList.IsIgnoredEx("some-folder/folder1/file1")
+	a.True(res)
+	a.Equal("tag", tag)
+
+	match := ignoreList.Explain("some-folder/file1")
+	a.True(match.Ignored)
+	if a.NotNil(match.Rule) {
+		a.Equal("tag", match.Rule.Tag)
+	}
+}
+
+func TestInheritance_includeFirst(t *testing.T) {
+	a := assert.New(t)
+	// any include pattern which matches the path re-includes it
+	ignoreList := newInheritanceList(a, EvaluationIncludeFirst, "not folder1/file1", "folder1", "not *.ex", "folder2/")
+	a.True(ignoreList.IsIgnored("folder1/file2"))
+	a.False(ignoreList.IsIgnored("folder1/file1"))
+	a.False(ignoreList.IsIgnored("folder1/folder2/a.ex"))
+	a.False(ignoreList.IsIgnored("folder2/a.ex"))
+	a.True(ignoreList.IsIgnored("folder2/folder1/file1"))
+}
+
+func TestInheritance_lastMatch(t *testing.T) {
+	a := assert.New(t)
+	// only the include patterns after the pattern which ignored the parent folder re-include the path
+	ignoreList := newInheritanceList(a, EvaluationLastMatch, "not folder1/file1", "folder1", "not *.ex", "folder2/")
+	a.True(ignoreList.IsIgnored("folder1/file2"))
+	a.True(ignoreList.IsIgnored("folder1/file1"))
+	a.False(ignoreList.IsIgnored("folder1/folder2/a.ex"))
+	a.True(ignoreList.IsIgnored("folder2/a.ex"))
+
+	match := ignoreList.Explain("folder1/file1")
+	if a.NotNil(match.Rule) {
+		a.Equal("folder1", match.Rule.Pattern)
+	}
+	if a.Len(match.Others, 1) {
+		a.Equal("folder1"+pathSeparator+"file1", match.Others[0].Pattern)
+	}
+}
+
+// The re-included folder is not ignored by its parent folder any more.
+func TestInheritance_includedFolder(t *testing.T) {
+	a := assert.New(t)
+	for _, evaluation := range []Evaluation{EvaluationIncludeFirst, EvaluationLastMatch} {
+		ignoreList := newInheritanceList(a, evaluation, "folder1/", "not folder1/folder2/", "folder1/folder2/folder3/")
+		a.True(ignoreList.IsIgnored("folder1/file1"))
+		a.False(ignoreList.IsIgnored("folder1/folder2/file1"))
+		a.Equal(evaluation == EvaluationLastMatch, ignoreList.IsIgnored("folder1/folder2/folder3/file1"))
+	}
+}
+
+func TestInheritance_walk(t *testing.T) {
+	a := assert.New(t)
+	fsys := fstest.MapFS{
+		"build/file1":         {},
+		"build/folder1/file1": {},
+		"folder1/file1":       {},
+	}
+	ignoreList := newInheritanceList(a, EvaluationDefault, "build")
+	ignoreList.SetInheritance(false)
+	a.Equal([]string{".", "build", "build/file1", "build/folder1", "build/folder1/file1", "folder1", "folder1/file1"},
+		walkedPaths(a, ignoreList, fsys, "."))
+
+	ignoreList.SetInheritance(true)
+	recorded := &recordFS{fsys: fsys}
+	a.Equal([]string{".", "folder1", "folder1/file1"}, walkedPaths(a, ignoreList, recorded, "."))
+	a.NotContains(recorded.opened, "build")
+}
+
+func TestInheritance_gitignore(t *testing.T) {
+	a := assert.New(t)
+	fsys := fstest.MapFS{
+		"a/x":   {},
+		"a/b/x": {},
+		"file1": {},
+	}
+	ignoreList := NewListWithDialect(DialectGitignore)
+	ignoreList.SetEvaluation(EvaluationIncludeFirst)
+	ignoreList.SetInheritance(true)
+	a.NoError(ignoreList.AddPattern("a/"))
+	a.False(ignoreList.IsIgnored("a/x"))
+	a.True(ignoreList.IsIgnoredDir("a"))
+
+	// the inheritance mode is not used by DialectGitignore, so the folder is not skipped
+	a.Equal([]string{".", "a/b", "a/b/x", "a/x", "file1"}, walkedPaths(a, ignoreList, fsys, "."))
+	match := ignoreList.Explain("a/x")
+	a.Nil(match.Rule)
+	a.Empty(match.Others)
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
@@ -0,0 +1,301 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"sort"
+	"strings"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// Matcher is a compiled immutable form of the ignore list which is optimized for the big pattern lists.
+// The prefixes of the patterns are indexed in a trie and the suffixes are indexed in a reversed trie,
+// the file patterns are indexed in a map, so checking a path does not depend on the number of the patterns
+// except the patterns with DialectGitignore and the patterns like "*text*" which are checked one by one.
+// The case-insensitive patterns are indexed separately and the folded path is used to find them.
+//
+// It gives the same results as the list it was compiled from.
+// The matcher is not changed when the list is changed, compile the list again to get the changes.
+// The matcher can be used from several goroutines simultaneously.
+type Matcher struct {
+	dialect     Dialect
+	evaluation  Evaluation
+	inheritance bool
+	normalizer  Normalizer
+	options     Options
+	patternList []pattern
+	exact       matcherIndex
+	folded      matcherIndex
+	others      []int
+}
+
+type matcherIndex struct {
+	files    map[string][]int
+	prefixes trieNode
+	suffixes trieNode
+}
+
+// Returns the compiled matcher of the current patterns.
+func (ignoreList *List) Compile() *Matcher {
+	// the snapshot is immutable, so its patterns are not copied
+	state := ignoreList.snapshot()
+	m := &Matcher{
+		dialect:     state.dialect,
+		evaluation:  state.actualEvaluation(),
+		inheritance: state.inherits(),
+		normalizer:  state.normalizer,
+		options:     state.options,
+		patternList: state.patternList,
+		exact:       matcherIndex{files: make(map[string][]int)},
+		folded:      matcherIndex{files: make(map[string][]int)},
+	}
+	for i := range m.patternList {
+		p := &m.patternList[i]
+		index := &m.exact
+		if p.caseInsensitive {
+			index = &m.folded
+		}
+		switch {
+		case p.glob != nil:
+			m.others = append(m.others, i)
+		case p.IsEmpty():
+			// it never matches
+		case p.isFile:
+			index.files[p.prefix] = append(index.files[p.prefix], i)
+		case p.HasPrefix():
+			index.prefixes.add(p.prefix, false, i)
+		case p.HasSuffix():
+			index.suffixes.add(p.suffix, true, i)
+		default:
+			m.others = append(m.others, i)
+		}
+	}
+	return m
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// It returns true if the given file path in the ignore list otherwise false.
+// See List.IsIgnored
+func (m *Matcher) IsIgnored(filePath string) bool {
+	res, _ := m.IsIgnoredEx(filePath)
+	return res
+}
+
+// It returns true if the given file path in the ignore list otherwise false.
+// Also it returns a tag.
+// See List.IsIgnoredEx
+func (m *Matcher) IsIgnoredEx(filePath string) (bool, string) {
+	if len(m.patternList) == 0 {
+		return false, ""
+	}
+	filePath = normalize(filePath, m.normalizer)
+	q, err := newQuery(&filePath, &m.options)
+	if err != nil {
+		return false, ""
+	}
+	return m.isIgnored(q)
+}
+
+// It returns true if the given folder path in the ignore list otherwise false.
+// See List.IsIgnoredDir
+func (m *Matcher) IsIgnoredDir(folderPath string) bool {
+	if len(m.patternList) == 0 {
+		return false
+	}
+	folderPath = normalize(folderPath, m.normalizer)
+	q, err := newQuery(&folderPath, &m.options)
+	if err != nil {
+		return false
+	}
+	res, _ := m.isIgnored(q.withType(true))
+	return res
+}
+
+func (m *Matcher) isIgnored(q *query) (bool, string) {
+	idx := m.decisivePattern(q)
+	if idx == -1 {
+		return false, ""
+	}
+	p := &m.patternList[idx]
+	return !p.include, p.tag
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+type trieNode struct {
+	children map[byte]*trieNode
+	// indices of the patterns which end on this node
+	patterns []int
+}
+
+func (n *trieNode) add(key string, reversed bool, idx int) {
+	for i := 0; i < len(key); i++ {
+		b := key[i]
+		if reversed {
+			b = key[len(key)-1-i]
+		}
+		if n.children == nil {
+			n.children = make(map[byte]*trieNode)
+		}
+		child, ok := n.children[b]
+		if !ok {
+			child = &trieNode{}
+			n.children[b] = child
+		}
+		n = child
+	}
+	n.patterns = append(n.patterns, idx)
+}
+
+func (n *trieNode) isEmpty() bool {
+	return len(n.children) == 0 && len(n.patterns) == 0
+}
+
+// Appends indices of the patterns which keys are prefixes (or suffixes if reversed) of the string.
+func (n *trieNode) collect(str string, reversed bool, out []int) []int {
+	for i := 0; n != nil; i++ {
+		out = append(out, n.patterns...)
+		if i == len(str) {
+			break
+		}
+		b := str[i]
+		if reversed {
+			b = str[len(str)-1-i]
+		}
+		n = n.children[b]
+	}
+	return out
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// Returns sorted indices of the patterns which match the query.
+func (m *Matcher) matchedPatterns(q *query) []int {
+	candidates := m.exact.collect(q.path, nil)
+	if !m.folded.isEmpty() {
+		candidates = m.folded.collect(q.folded().path, candidates)
+	}
+	candidates = append(candidates, m.others...)
+
+	matched := candidates[:0]
+	for _, idx := range candidates {
+		if m.patternList[idx].isMatched(q) {
+			matched = append(matched, idx)
+		}
+	}
+	sort.Ints(matched)
+	return matched
+}
+
+// Returns sorted indices of the patterns which match the query in the inheritance mode.
+// See List.matchedInTree
+func (m *Matcher) matchedInTree(q *query) []int {
+	matched := m.matchedPatterns(q)
+	if !q.isDir || len(q.elements) == 0 {
+		return matched
+	}
+	folderPath := strings.TrimSuffix(q.path, q.separator)
+	candidates := m.exact.files[folderPath]
+	if !m.folded.isEmpty() {
+		candidates = append(candidates[:len(candidates):len(candidates)], m.folded.files[foldCase(folderPath)]...)
+	}
+	var files []int
+	for _, idx := range candidates {
+		if m.patternList[idx].isMatchedInTree(q) {
+			files = append(files, idx)
+		}
+	}
+	sort.Ints(files)
+	return mergeIndices(matched, files)
+}
+
+// Appends indices of the patterns which can match the path.
+func (index *matcherIndex) collect(path string, out []int) []int {
+	out = append(out, index.files[path]...)
+	out = index.prefixes.collect(path, false, out)
+	return index.suffixes.collect(path, true, out)
+}
+
+func (index *matcherIndex) isEmpty() bool {
+	return len(index.files) == 0 && index.prefixes.isEmpty() && index.suffixes.isEmpty()
+}
+
+// Returns index of the pattern which decides whether the path is ignored or -1.
+// See List.decisivePattern
+func (m *Matcher) decisivePattern(q *query) int {
+	if m.inheritance {
+		return inheritedPattern(m.patternList, m.evaluation, q, m.matchedInTree)
+	}
+	if m.evaluation == EvaluationIncludeFirst {
+		return decisiveIncludeFirst(m.patternList, m.matchedPatterns(q))
+	}
+	if m.dialect == DialectGitignore {
+		for i := 1; i < len(q.elements); i++ {
+			idx := decisiveLastMatch(m.matchedPatterns(q.parent(i)))
+			if idx != -1 && !m.patternList[idx].include {
+				return idx
+			}
+		}
+	}
+	return decisiveLastMatch(m.matchedPatterns(q))
+}
+
+func decisiveIncludeFirst(patternList []pattern, matched []int) int {
+	for _, idx := range matched {
+		if patternList[idx].include {
+			return idx
+		}
+	}
+	if len(matched) != 0 {
+		return matched[0]
+	}
+	return -1
+}
+
+func decisiveLastMatch(matched []int) int {
+	if len(matched) != 0 {
+		return matched[len(matched)-1]
+	}
+	return -1
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
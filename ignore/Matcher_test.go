@@ -0,0 +1,182 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func matcherTestPaths() []string {
+	return append(filePathList(), append(prefixSuffixList(),
+		"",
+		"folder1",
+		"folder1/",
+		"folder2/",
+		"a.ex",
+		"folder1/a.ex",
+		"build/debug/cache/1.tmp",
+		"build/debug/1.tmp",
+		"folder2/folder1/file1/a.ex",
+		"FOLDER1/A.EX",
+		"Folder2/File1",
+		"caf\u00e9/menu",
+		"folder1/caf\u00e9",
+	)...)
+}
+
+// Returns the lists of the different kinds of patterns.
+func matcherTestLists() [][]string {
+	return [][]string{
+		{},
+		{"*", "folder1"},
+		{"folder1/", "folder2/*"},
+		{"folder1/file1", "folder2:folder1\\file1", "folder1/file1"},
+		{"prefix 1*", "*suffix 2", "prefix*suffix 1"},
+		{"*.ex", "not folder1/*.ex", "[tag] folder2/*", "!folder2/folder1/*"},
+		{"build/*/cache/*.tmp", "*ol*er*", "not *older1*"},
+		{"[t1] folder2/*", "[t2] not folder2/*", "[t3] folder2/*", "[t4] *1"},
+		{"(?i)*.EX", "not (?i)Folder1/*", "(?i)folder2/file1", "*.ex", "(?i)*OLDER*"},
+		{"caf\u00e9/*", "*\u00e9", "not folder1/caf\u00e9"},
+		{"folder1", "(?i)Folder2/Folder1", "not folder1/file1", "folder2/", "not folder2/folder1/file1/"},
+	}
+}
+
+func checkMatcher(a *assert.Assertions, ignoreList *List, name string) {
+	matcher := ignoreList.Compile()
+	for _, p := range matcherTestPaths() {
+		res1, tag1 := ignoreList.IsIgnoredEx(p)
+		res2, tag2 := matcher.IsIgnoredEx(p)
+		a.Equal(res1, res2, "%s: %s", name, p)
+		a.Equal(tag1, tag2, "%s: %s", name, p)
+	}
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func TestMatcherSameResults(t *testing.T) {
+	a := assert.New(t)
+	for i, lines := range matcherTestLists() {
+		ignoreList, err := NewListFromLines(lines)
+		a.NoError(err)
+		checkMatcher(a, ignoreList, fmt.Sprintf("include first %d", i))
+		ignoreList.SetEvaluation(EvaluationLastMatch)
+		checkMatcher(a, ignoreList, fmt.Sprintf("last match %d", i))
+		ignoreList.SetInheritance(true)
+		checkMatcher(a, ignoreList, fmt.Sprintf("last match inheritance %d", i))
+		ignoreList.SetEvaluation(EvaluationIncludeFirst)
+		checkMatcher(a, ignoreList, fmt.Sprintf("include first inheritance %d", i))
+	}
+}
+
+func TestMatcherSameResults_gitignore(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := newGitignoreList(a, "*.ex", "!folder1/*.ex", "folder2/", "!folder2/folder1/", "/folder1", "file?")
+	checkMatcher(a, ignoreList, "gitignore")
+}
+
+// The matcher is not changed when the list is changed
+func TestMatcherImmutable(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := NewList()
+	ignoreList.AddPattern("folder1/*")
+	matcher := ignoreList.Compile()
+	ignoreList.Clear()
+	ignoreList.AddPattern("folder2/*")
+	a.True(matcher.IsIgnored("folder1/file1"))
+	a.False(matcher.IsIgnored("folder2/file1"))
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// Returns the generated list with the specified number of the patterns of all the native kinds.
+func generatedList(patternsNum int) *List {
+	ignoreList := NewList()
+	for i := 0; i < patternsNum; i++ {
+		switch i % 4 {
+		case 0:
+			ignoreList.AddPattern(fmt.Sprintf("generated/folder%d/*", i))
+		case 1:
+			ignoreList.AddPattern(fmt.Sprintf("*.ext%d", i))
+		case 2:
+			ignoreList.AddPattern(fmt.Sprintf("generated/file%d", i))
+		case 3:
+			ignoreList.AddPattern(fmt.Sprintf("not generated/folder%d/*.keep%d", i-3, i))
+		}
+	}
+	return ignoreList
+}
+
+func benchmarkPaths() []string {
+	return []string{
+		"generated/folder40/sub/file.txt",
+		"generated/folder40/sub/file.keep43",
+		"other/folder/file.ext41",
+		"generated/file42",
+		"other/folder/file.txt",
+	}
+}
+
+func BenchmarkList(b *testing.B) {
+	for _, num := range []int{50, 500, 5000} {
+		ignoreList := generatedList(num)
+		paths := benchmarkPaths()
+		b.Run(fmt.Sprintf("patterns-%d", num), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				ignoreList.IsIgnored(paths[i%len(paths)])
+			}
+		})
+	}
+}
+
+func BenchmarkMatcher(b *testing.B) {
+	for _, num := range []int{50, 500, 5000} {
+		matcher := generatedList(num).Compile()
+		paths := benchmarkPaths()
+		b.Run(fmt.Sprintf("patterns-%d", num), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				matcher.IsIgnored(paths[i%len(paths)])
+			}
+		})
+	}
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
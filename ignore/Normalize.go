@@ -0,0 +1,77 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// Normalizer converts a text to a Unicode normalization form.
+// The forms of the package golang.org/x/text/unicode/norm like norm.NFC and norm.NFD implement it.
+type Normalizer interface {
+	String(str string) string
+}
+
+// NormalizerFunc allows to use an ordinary function as a Normalizer.
+type NormalizerFunc func(str string) string
+
+func (f NormalizerFunc) String(str string) string {
+	return f(str)
+}
+
+// Sets the normalizer which is applied to the patterns and to the checked paths,
+// so the visually identical names match even if they have different Unicode forms.
+// E.g. macOS gives the file names in NFD but the ignore files are usually written in NFC:
+//
+// ignoreList.SetNormalizer(norm.NFC)
+//
+// Nil normalizer disables the normalization.
+func (ignoreList *List) SetNormalizer(normalizer Normalizer) {
+	ignoreList.update(func(state *listState) error {
+		state.normalizer = normalizer
+		state.reprocess()
+		return nil
+	})
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func normalize(str string, normalizer Normalizer) string {
+	if normalizer == nil {
+		return str
+	}
+	return normalizer.String(str)
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
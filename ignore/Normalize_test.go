@@ -0,0 +1,126 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// The composition of a few letters instead of the real NFC to avoid the dependency.
+var testNFC = NormalizerFunc(strings.NewReplacer("e\u0301", "\u00e9", "a\u0308", "\u00e4").Replace)
+
+const (
+	cafeNFC = "caf\u00e9"
+	cafeNFD = "cafe\u0301"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func TestNormalizer(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := NewList()
+	ignoreList.AddPattern(cafeNFC + "/*")
+	a.True(ignoreList.IsIgnored(cafeNFC + "/menu"))
+	a.False(ignoreList.IsIgnored(cafeNFD + "/menu"))
+
+	ignoreList.SetNormalizer(testNFC)
+	a.True(ignoreList.IsIgnored(cafeNFC + "/menu"))
+	a.True(ignoreList.IsIgnored(cafeNFD + "/menu"))
+	a.True(ignoreList.Compile().IsIgnored(cafeNFD + "/menu"))
+	a.True(ignoreList.Explain(cafeNFD + "/menu").Ignored)
+
+	// the patterns are normalized too
+	ignoreList.AddPattern("not *" + cafeNFD + ".txt")
+	a.Equal("*"+cafeNFC+".txt", ignoreList.Rules()[1].Pattern)
+	a.False(ignoreList.IsIgnored(cafeNFC + "/" + cafeNFC + ".txt"))
+	a.False(ignoreList.IsIgnored(cafeNFD + "/" + cafeNFD + ".txt"))
+
+	// the patterns which are already in the list are processed again
+	ignoreList.SetNormalizer(nil)
+	a.False(ignoreList.IsIgnored(cafeNFD + "/menu"))
+	a.Equal("*"+cafeNFD+".txt", ignoreList.Rules()[1].Pattern)
+}
+
+func TestNormalizer_load(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := NewList()
+	ignoreList.SetNormalizer(testNFC)
+	ignoreList.SetCaseInsensitive(true)
+	a.NoError(ignoreList.LoadFromReader(strings.NewReader("[tag] Cafe\u0301/*.ma\u0308p")))
+	res, tag := ignoreList.IsIgnoredEx(cafeNFD + "/1.m\u00e4p")
+	a.True(res)
+	a.Equal("tag", tag)
+}
+
+func TestNormalizer_gitignore(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := NewListWithDialect(DialectGitignore)
+	ignoreList.SetNormalizer(testNFC)
+	a.NoError(ignoreList.AddPattern(cafeNFD + "/"))
+	a.NoError(ignoreList.AddPattern("!" + cafeNFD + "/keep"))
+	a.True(ignoreList.IsIgnored(cafeNFC + "/"))
+	a.True(ignoreList.IsIgnored(cafeNFD + "/menu"))
+	a.True(ignoreList.IsIgnored(cafeNFC + "/keep"))
+}
+
+func TestNormalizer_walk(t *testing.T) {
+	a := assert.New(t)
+	fsys := fstest.MapFS{
+		cafeNFD + "/menu":    {},
+		cafeNFD + "/keep":    {},
+		"folder1/" + cafeNFD: {},
+		"folder1/file1":      {},
+	}
+	ignoreList, err := NewListFromLines([]string{cafeNFC + "/*", "*" + cafeNFC})
+	a.NoError(err)
+	a.Equal([]string{".", cafeNFD, cafeNFD + "/keep", cafeNFD + "/menu", "folder1", "folder1/" + cafeNFD, "folder1/file1"},
+		walkedPaths(a, ignoreList, fsys, "."))
+
+	ignoreList.SetNormalizer(testNFC)
+	ignoreList.LoadFromReader(strings.NewReader(cafeNFC + "/*\n*" + cafeNFC))
+	a.Equal([]string{".", "folder1", "folder1/file1"}, walkedPaths(a, ignoreList, fsys, "."))
+
+	tree := NewTree(".ignore")
+	tree.SetList("", ignoreList)
+	a.True(tree.IsIgnored(cafeNFD + "/menu"))
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
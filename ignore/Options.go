@@ -0,0 +1,168 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// Options describes how the patterns and the checked paths are split into the path elements.
+//
+// By default the symbols \ / : are separators and all of them are replaced with os.PathSeparator,
+// so the same ignore file works on all the operating systems, but the patterns are kept
+// with the different separators on different systems and ":" can not be a part of a file name.
+// The following options give the identical results on all the systems and treat ":" as a regular symbol:
+//
+// ignoreList.SetOptions(Options{Separators: "\\/", CanonicalSeparator: '/'})
+//
+// The checked paths are cleaned like path.Clean does before matching: the "." elements are removed
+// and the ".." elements are resolved, so "./folder/a" and "folder/b/../a" are the same as "folder/a".
+// A relative path which goes outside the root with ".." is never ignored, see List.Resolve.
+type Options struct {
+	// The symbols which separate the path elements, the default ones are used if it is empty.
+	Separators string
+	// The separator which replaces all the others in the patterns and in the checked paths,
+	// os.PathSeparator is used if it is 0. It is always a separator even if Separators does not contain it.
+	CanonicalSeparator rune
+	// The folder which the patterns are relative to.
+	// The absolute paths inside the folder are made relative to it before matching
+	// and the absolute paths outside the folder are never ignored.
+	// The absolute paths are matched as they are if it is empty.
+	BaseDir string
+}
+
+// It is returned (wrapped into *fs.PathError) when a path goes outside the root or the base folder.
+var ErrOutsideRoot = errors.New("the path is outside the root folder")
+
+// Sets the options of the list.
+func (ignoreList *List) SetOptions(options Options) {
+	ignoreList.update(func(state *listState) error {
+		state.options = options
+		state.reprocess()
+		return nil
+	})
+}
+
+// Returns the options of the list.
+func (ignoreList *List) Options() Options {
+	return ignoreList.snapshot().options
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// Returns all the separators and the canonical separator with the defaults applied.
+func (options *Options) separators() (string, string) {
+	separator := pathSeparator
+	if options.CanonicalSeparator != 0 {
+		separator = string(options.CanonicalSeparator)
+	}
+	separators := options.Separators
+	if len(separators) == 0 {
+		separators = defaultSeparators
+	}
+	if !strings.Contains(separators, separator) {
+		separators += separator
+	}
+	return separators, separator
+}
+
+// Splits the path with the canonical separator into the elements, removes the "." elements,
+// resolves the ".." elements and makes the absolute path relative to the base folder.
+// It returns true if the elements are not the same as the path has.
+func (options *Options) splitPath(filePath string, fixedPath string, separator string) ([]string, bool, error) {
+	elements, changed, err := cleanElements(fixedPath, separator)
+	if err != nil {
+		return nil, false, &fs.PathError{Op: "resolve", Path: filePath, Err: err}
+	}
+	if len(options.BaseDir) == 0 || !(strings.HasPrefix(fixedPath, separator) || filepath.IsAbs(filePath)) {
+		return elements, changed, nil
+	}
+	fixedBaseDir := fixSeparator(&options.BaseDir, options)
+	baseElements, _, _ := cleanElements(*fixedBaseDir, separator)
+	if len(elements) < len(baseElements) {
+		return nil, false, &fs.PathError{Op: "resolve", Path: filePath, Err: ErrOutsideRoot}
+	}
+	for i := range baseElements {
+		if elements[i] != baseElements[i] {
+			return nil, false, &fs.PathError{Op: "resolve", Path: filePath, Err: ErrOutsideRoot}
+		}
+	}
+	return elements[len(baseElements):], true, nil
+}
+
+// The ".." element of a relative path can not go outside the root,
+// the ".." element of an absolute path is ignored in the root like path.Clean does.
+func cleanElements(fixedPath string, separator string) ([]string, bool, error) {
+	var elements []string
+	changed := false
+	for _, e := range strings.Split(fixedPath, separator) {
+		switch e {
+		case "":
+		case ".":
+			changed = true
+		case "..":
+			changed = true
+			if len(elements) != 0 {
+				elements = elements[:len(elements)-1]
+			} else if !strings.HasPrefix(fixedPath, separator) {
+				return nil, false, ErrOutsideRoot
+			}
+		default:
+			elements = append(elements, e)
+		}
+	}
+	return elements, changed, nil
+}
+
+// Returns the path of a file system with the canonical separator instead of "/".
+// The folders get the trailing separator.
+func fsPath(filePath string, isDir bool, options *Options) string {
+	_, separator := options.separators()
+	if separator != "/" {
+		filePath = strings.ReplaceAll(filePath, "/", separator)
+	}
+	if isDir && !strings.HasSuffix(filePath, separator) {
+		filePath += separator
+	}
+	return filePath
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
@@ -0,0 +1,235 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func newListWithOptions(a *assert.Assertions, options Options, lines ...string) *List {
+	ignoreList := NewList()
+	ignoreList.SetOptions(options)
+	for _, line := range lines {
+		a.NoError(ignoreList.AddPattern(line))
+	}
+	return ignoreList
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func TestOptions_default(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := newListWithOptions(a, Options{}, "folder1:*")
+	a.Equal(Options{}, ignoreList.Options())
+	a.Equal("folder1"+pathSeparator+"*", ignoreList.Rules()[0].Pattern)
+	a.True(ignoreList.IsIgnored("folder1/file1"))
+	a.True(ignoreList.IsIgnored("folder1\\file1"))
+	a.True(ignoreList.IsIgnored("folder1:file1"))
+}
+
+func TestOptions_canonicalSeparator(t *testing.T) {
+	a := assert.New(t)
+	options := Options{Separators: "\\/", CanonicalSeparator: '/'}
+	ignoreList := newListWithOptions(a, options, "folder1\\*.ex", "folder2\\file1", "not folder1/folder2/")
+	a.Equal(options, ignoreList.Options())
+	a.Equal([]string{"folder1/*.ex", "folder2/file1", "folder1/folder2/*"},
+		[]string{ignoreList.Rules()[0].Pattern, ignoreList.Rules()[1].Pattern, ignoreList.Rules()[2].Pattern})
+
+	a.True(ignoreList.IsIgnored("folder1\\a.ex"))
+	a.True(ignoreList.IsIgnored("folder1//a.ex"))
+	a.True(ignoreList.IsIgnored("folder2/file1"))
+	a.False(ignoreList.IsIgnored("folder1/folder2/a.ex"))
+	// ":" is a regular symbol
+	a.False(ignoreList.IsIgnored("folder1:a.ex"))
+	a.False(ignoreList.IsIgnored("folder2:file1"))
+
+	// the compiled matcher gives the same results
+	matcher := ignoreList.Compile()
+	a.True(matcher.IsIgnored("folder1\\a.ex"))
+	a.False(matcher.IsIgnored("folder1:a.ex"))
+}
+
+func TestOptions_literalSeparators(t *testing.T) {
+	a := assert.New(t)
+	options := Options{Separators: "/", CanonicalSeparator: '/'}
+	ignoreList := newListWithOptions(a, options, "c:*", "folder1\\file1")
+	a.True(ignoreList.IsIgnored("c:/file1"))
+	a.True(ignoreList.IsIgnored("c:file1"))
+	a.False(ignoreList.IsIgnored("c/file1"))
+	a.True(ignoreList.IsIgnored("folder1\\file1"))
+	a.False(ignoreList.IsIgnored("folder1/file1"))
+}
+
+func TestOptions_invalidUTF8(t *testing.T) {
+	a := assert.New(t)
+	options := Options{Separators: "\\/", CanonicalSeparator: '/'}
+	ignoreList := newListWithOptions(a, options, "a\\\xff")
+	a.Equal("a/\xff", ignoreList.Rules()[0].Pattern)
+	a.True(ignoreList.IsIgnored("a/\xff"))
+	a.False(ignoreList.IsIgnored("a/\xfe"))
+	a.False(ignoreList.Compile().IsIgnored("a\\\xfe"))
+	resolved, err := ignoreList.Resolve("a\\\xfe")
+	a.NoError(err)
+	a.Equal("a/\xfe", resolved)
+}
+
+func TestOptions_reprocess(t *testing.T) {
+	a := assert.New(t)
+	ignoreList, err := NewListFromString("a:b/*\n[@if release]\n[tag] not a:b/keep\n[@end]\n")
+	a.NoError(err)
+	ignoreList.Combine(newGitignoreList(a, "c:d/*"))
+	a.True(ignoreList.IsIgnored("a/b/c"))
+
+	// the patterns which are already in the list are processed with the new options
+	ignoreList.SetOptions(Options{Separators: "/", CanonicalSeparator: '/'})
+	a.True(ignoreList.IsIgnored("a:b/c"))
+	a.False(ignoreList.IsIgnored("a/b/c"))
+	a.True(ignoreList.IsIgnored("c:d/file1"))
+	a.False(ignoreList.IsIgnored("c/d/file1"))
+	rules := ignoreList.Rules()
+	if a.Len(rules, 3) {
+		a.Equal("a:b/*", rules[0].Pattern)
+		a.Equal(Rule{Pattern: "a:b/keep", Include: true, Tag: "tag", Tags: []string{"tag"},
+			Origin: Origin{Text: "[tag] not a:b/keep", Line: 3}, Conditions: []string{"release"}}, rules[1])
+		a.Equal("c:d/*", rules[2].Pattern)
+	}
+	a.False(ignoreList.IsIgnoredFor("a:b/keep", "release", "tag"))
+	a.True(ignoreList.IsIgnoredFor("a:b/keep"))
+}
+
+func TestOptions_gitignore(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := NewListWithDialect(DialectGitignore)
+	ignoreList.SetOptions(Options{Separators: "/", CanonicalSeparator: '/'})
+	a.NoError(ignoreList.AddPattern("a:b/"))
+	a.True(ignoreList.IsIgnored("a:b/"))
+	a.True(ignoreList.IsIgnored("folder1/a:b/file1"))
+	a.False(ignoreList.IsIgnored("a/b/file1"))
+}
+
+func TestOptions_walk(t *testing.T) {
+	a := assert.New(t)
+	fsys := fstest.MapFS{
+		"a:b/file1":     {},
+		"a/b/file1":     {},
+		"folder1/file1": {},
+	}
+	ignoreList := newListWithOptions(a, Options{Separators: "\\", CanonicalSeparator: '\\'}, "a:b\\*", "folder1\\file1")
+	a.Equal([]string{".", "a", "a/b", "a/b/file1", "folder1"}, walkedPaths(a, ignoreList, fsys, "."))
+
+	tree := NewTree(".ignore")
+	tree.SetOptions(Options{Separators: "/", CanonicalSeparator: '/'})
+	tree.SetList("a:b", newListWithOptions(a, Options{Separators: "/", CanonicalSeparator: '/'}, "file1"))
+	a.True(tree.IsIgnored("a:b/file1"))
+	a.False(tree.IsIgnored("a/b/file1"))
+	a.NotNil(tree.List("a:b/"))
+	a.Nil(tree.List("a"))
+}
+
+func TestOptions_cleanPath(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := newListWithOptions(a, Options{CanonicalSeparator: '/'}, "folder1/file1", "folder2/")
+	for _, p := range []string{"folder1/file1", "./folder1/file1", "folder1//file1", "folder1/./file1",
+		"folder2/../folder1/file1", "folder1/file1/."} {
+		resolved, err := ignoreList.Resolve(p)
+		a.NoError(err, p)
+		a.Equal("folder1/file1", resolved, p)
+		a.True(ignoreList.IsIgnored(p), p)
+		a.True(ignoreList.Compile().IsIgnored(p), p)
+	}
+	resolved, err := ignoreList.Resolve("./folder2/folder1/../")
+	a.NoError(err)
+	a.Equal("folder2/", resolved)
+
+	// the absolute paths are kept absolute without the base folder
+	resolved, err = ignoreList.Resolve("/folder1/./file1")
+	a.NoError(err)
+	a.Equal("/folder1/file1", resolved)
+	resolved, err = ignoreList.Resolve("/../folder1")
+	a.NoError(err)
+	a.Equal("/folder1", resolved)
+}
+
+func TestOptions_outsideRoot(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := newListWithOptions(a, Options{CanonicalSeparator: '/'}, "*1")
+	for _, p := range []string{"../folder1", "folder1/../../folder1", ".."} {
+		_, err := ignoreList.Resolve(p)
+		a.ErrorIs(err, ErrOutsideRoot, p)
+		var pathErr *fs.PathError
+		if a.ErrorAs(err, &pathErr) {
+			a.Equal(p, pathErr.Path)
+		}
+		a.False(ignoreList.IsIgnored(p), p)
+		a.False(ignoreList.Compile().IsIgnored(p), p)
+		a.Nil(ignoreList.Explain(p).Rule, p)
+	}
+}
+
+func TestOptions_baseDir(t *testing.T) {
+	a := assert.New(t)
+	options := Options{CanonicalSeparator: '/', BaseDir: "/abs/root/"}
+	ignoreList := newListWithOptions(a, options, "folder1/*")
+	for _, p := range []string{"/abs/root/folder1/file1", "/abs//root/./folder1/file1", "/abs/other/../root/folder1/file1",
+		"folder1/file1"} {
+		resolved, err := ignoreList.Resolve(p)
+		a.NoError(err, p)
+		a.Equal("folder1/file1", resolved, p)
+		a.True(ignoreList.IsIgnored(p), p)
+	}
+	for _, p := range []string{"/abs/other/folder1/file1", "/abs", "/abs/root/../folder1/file1"} {
+		_, err := ignoreList.Resolve(p)
+		a.ErrorIs(err, ErrOutsideRoot, p)
+		a.False(ignoreList.IsIgnored(p), p)
+	}
+	resolved, err := ignoreList.Resolve("/abs/root")
+	a.NoError(err)
+	a.Equal("", resolved)
+
+	tree := NewTree(".ignore")
+	tree.SetOptions(options)
+	tree.SetList("/abs/root/folder2", newListWithOptions(a, options, "file1"))
+	a.NotNil(tree.List("folder2"))
+	a.True(tree.IsIgnored("/abs/root/folder2/file1"))
+	a.False(tree.IsIgnored("/abs/other/folder2/file1"))
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
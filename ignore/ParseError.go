@@ -0,0 +1,122 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// It is returned (wrapped into *ParseError) when a tag does not have the closing "]" symbol.
+var ErrTagNotClosed = errors.New("tag is not closed, you must use <]> symbol to close it")
+
+// It is returned (wrapped into *ParseError) when a file includes itself directly or through other files.
+var ErrIncludeCycle = errors.New("the file is already being included")
+
+// It is returned (wrapped into *ParseError) when the includes are nested too deep.
+var ErrIncludeDepth = errors.New("too many nested includes")
+
+// It is returned (wrapped into *ParseError) when a block does not have a condition.
+var ErrBlockCondition = errors.New("the block does not have a condition, you must use [@if tag]")
+
+// It is returned (wrapped into *ParseError) when a block does not have the closing [@end] line.
+var ErrBlockNotClosed = errors.New("the block is not closed, you must use [@end] to close it")
+
+// It is returned (wrapped into *ParseError) when the [@end] line does not have the opening [@if tag] line.
+var ErrBlockNotOpened = errors.New("the block is not opened, you must use [@if tag] to open it")
+
+// ParseError describes a problem with a pattern.
+type ParseError struct {
+	// Path of the file which contains the pattern.
+	// It is empty if the pattern was not loaded from a file.
+	File string
+	// Number of the line, the first line is 1.
+	// It is 0 if the pattern was added with AddPattern.
+	Line int
+	// Position of the problem in the Text, the first symbol is 1.
+	Column int
+	// The text of the incorrect line or of the AddPattern argument.
+	Text string
+	// The problem.
+	Err error
+}
+
+func newParseError(origin Origin, column int, err error) *ParseError {
+	if column < 1 {
+		column = 1
+	}
+	return &ParseError{File: origin.File, Line: origin.Line, Column: column, Text: origin.Text, Err: err}
+}
+
+func (e *ParseError) Error() string {
+	if len(e.File) != 0 {
+		return fmt.Sprintf("%s:%d:%d: %s <%s>", e.File, e.Line, e.Column, e.Err, e.Text)
+	}
+	if e.Line != 0 {
+		return fmt.Sprintf("%d:%d: %s <%s>", e.Line, e.Column, e.Err, e.Text)
+	}
+	return fmt.Sprintf("column %d: %s <%s>", e.Column, e.Err, e.Text)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// ParseErrors contains all the problems which were found while loading in the lenient mode.
+type ParseErrors []*ParseError
+
+func (e ParseErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+func (e ParseErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
@@ -0,0 +1,122 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"path"
+	"testing"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func TestParseError_case1(t *testing.T) {
+	a := assert.New(t)
+	writeIgnoreListFile([]string{"folder1/*", "", "  [tag folder2/*"})
+	ignoreList, err := NewListFromFile(filePath)
+	var parseErr *ParseError
+	if a.True(errors.As(err, &parseErr)) {
+		a.Equal(&ParseError{File: filePath, Line: 3, Column: 3, Text: "  [tag folder2/*", Err: ErrTagNotClosed}, parseErr)
+		a.Equal(filePath+":3:3: "+ErrTagNotClosed.Error()+" <  [tag folder2/*>", parseErr.Error())
+	}
+	a.True(errors.Is(err, ErrTagNotClosed))
+	a.Empty(ignoreList.Rules())
+	removeIgnoreListFile()
+}
+
+func TestParseError_case2(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := NewList()
+	err := ignoreList.AddPattern("[tag")
+	var parseErr *ParseError
+	if a.True(errors.As(err, &parseErr)) {
+		a.Equal(&ParseError{Column: 1, Text: "[tag", Err: ErrTagNotClosed}, parseErr)
+		a.Equal("column 1: "+ErrTagNotClosed.Error()+" <[tag>", parseErr.Error())
+	}
+	a.NoError(ignoreList.AddPattern("folder1/*"))
+}
+
+func TestParseError_gitignore(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := NewListWithDialect(DialectGitignore)
+	err := ignoreList.AddPattern("!  folder[1/*")
+	var parseErr *ParseError
+	if a.True(errors.As(err, &parseErr)) {
+		a.Equal(2, parseErr.Column)
+	}
+	a.True(errors.Is(err, path.ErrBadPattern))
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func TestLenient_case1(t *testing.T) {
+	a := assert.New(t)
+	writeIgnoreListFile([]string{"[tag1 folder1/*", "folder2/*", "[tag2 folder3/*", "not folder2/folder1/*"})
+	ignoreList := NewList()
+	ignoreList.SetLenient(true)
+	err := ignoreList.LoadFromFile(filePath)
+
+	var parseErrors ParseErrors
+	if a.True(errors.As(err, &parseErrors)) && a.Len(parseErrors, 2) {
+		a.Equal(1, parseErrors[0].Line)
+		a.Equal(3, parseErrors[1].Line)
+		a.Equal(parseErrors[0].Error()+"\n"+parseErrors[1].Error(), err.Error())
+	}
+	var parseErr *ParseError
+	if a.True(errors.As(err, &parseErr)) {
+		a.Equal("[tag1 folder1/*", parseErr.Text)
+	}
+	a.True(errors.Is(err, ErrTagNotClosed))
+
+	fpList := filePathList()
+	a.False(ignoreList.IsIgnored(fpList[0])) // "folder1/file1"
+	a.False(ignoreList.IsIgnored(fpList[2])) // "folder2/folder1/file1"
+	a.True(ignoreList.IsIgnored(fpList[3]))  // "folder2/folder2/file1"
+	removeIgnoreListFile()
+}
+
+func TestLenient_case2(t *testing.T) {
+	a := assert.New(t)
+	writeIgnoreListFile([]string{"folder1/*", "folder2/*"})
+	ignoreList := NewList()
+	ignoreList.SetLenient(true)
+	a.NoError(ignoreList.LoadFromFile(filePath))
+	a.Len(ignoreList.Rules(), 2)
+	removeIgnoreListFile()
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
@@ -0,0 +1,134 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// Writes the patterns of the ignore list in the same order, one pattern per line.
+// Every pattern is written in the canonical form with its tag, "not " (or "!" for DialectGitignore),
+// the "(?i)" flag and the comment (if the inline comments are enabled), e.g. "some-folder/" is written as "some-folder/*".
+// The comment lines are not kept in the list, so they are not written.
+// The patterns with conditions are written in the [@if tag] blocks.
+//
+// Loading the result into a list with the same dialect and options gives the equivalent list.
+// The settings of the list like the evaluation are not written.
+func (ignoreList *List) WriteTo(writer io.Writer) (int64, error) {
+	state := ignoreList.snapshot()
+	counter := &countingWriter{writer: writer}
+	buffer := bufio.NewWriter(counter)
+	var conditions []string
+	for i := range state.patternList {
+		conditions = writeBlocks(buffer, conditions, state.patternList[i].conditions)
+		buffer.WriteString(state.patternList[i].line(state.dialect, state.inlineComments))
+		buffer.WriteByte('\n')
+	}
+	writeBlocks(buffer, conditions, nil)
+	err := buffer.Flush()
+	return counter.count, err
+}
+
+// Returns the patterns of the ignore list in the same form as WriteTo writes them.
+func (ignoreList *List) String() string {
+	var builder strings.Builder
+	ignoreList.WriteTo(&builder)
+	return builder.String()
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// Returns the line which gives the same pattern after processing.
+func (s *pattern) line(dialect Dialect, inlineComments bool) string {
+	var builder strings.Builder
+	if dialect == DialectGitignore {
+		if s.include {
+			builder.WriteString(not2)
+		}
+	} else {
+		// the pattern which starts with "[" needs a tag, otherwise it is processed as a tag,
+		// the tag also prevents processing the pattern as the include directive
+		if len(s.tag) != 0 || strings.HasPrefix(s.String(), "[") || strings.HasPrefix(s.String(), includeDirective) {
+			builder.WriteString("[" + s.tag + "] ")
+		}
+		if s.include {
+			builder.WriteString(not1)
+		}
+	}
+	if s.caseInsensitive {
+		builder.WriteString(caseInsensitiveFlag)
+	}
+	builder.WriteString(s.String())
+	if len(s.comment) != 0 && inlineComments {
+		builder.WriteString("  " + commentStart + " " + s.comment)
+	}
+	if dialect != DialectGitignore && strings.HasPrefix(builder.String(), commentStart) {
+		return "\\" + builder.String()
+	}
+	return builder.String()
+}
+
+// Closes the blocks of the previous pattern which the next one is not in and opens the blocks of the next one.
+func writeBlocks(buffer *bufio.Writer, previous []string, next []string) []string {
+	common := 0
+	for common < len(previous) && common < len(next) && previous[common] == next[common] {
+		common++
+	}
+	for i := common; i < len(previous); i++ {
+		buffer.WriteString(blockEnd + "\n")
+	}
+	for _, condition := range next[common:] {
+		buffer.WriteString(blockStart + condition + "]\n")
+	}
+	return next
+}
+
+type countingWriter struct {
+	writer io.Writer
+	count  int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.count += int64(n)
+	return n, err
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
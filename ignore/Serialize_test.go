@@ -0,0 +1,153 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// Returns the patterns of the list without origins.
+func patternsWithoutOrigin(ignoreList *List) []pattern {
+	var patternList []pattern
+	for _, p := range ignoreList.snapshot().patternList {
+		p.origin = Origin{}
+		patternList = append(patternList, p)
+	}
+	return patternList
+}
+
+// Writes the list, loads the result into a new list with the same settings and compares the patterns.
+func checkRoundTrip(a *assert.Assertions, ignoreList *List) {
+	var buffer bytes.Buffer
+	n, err := ignoreList.WriteTo(&buffer)
+	a.NoError(err)
+	a.Equal(int64(buffer.Len()), n)
+	a.Equal(buffer.String(), ignoreList.String())
+
+	state := ignoreList.snapshot()
+	loaded := NewListWithDialect(state.dialect)
+	loaded.SetOptions(state.options)
+	loaded.SetInlineComments(state.inlineComments)
+	a.NoError(loaded.LoadFromReader(&buffer))
+	a.Equal(patternsWithoutOrigin(ignoreList), patternsWithoutOrigin(loaded), ignoreList.String())
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func TestWriteTo(t *testing.T) {
+	a := assert.New(t)
+	ignoreList, err := NewListFromLines([]string{
+		"  [tag 1]   folder1/  ",
+		"!folder1/file1",
+		"[] not (?i)*.EX",
+		"folder2:folder1\\*.tmp",
+		"*",
+		"[ ] [file",
+		"build/*/cache/**.tmp",
+	})
+	a.NoError(err)
+	s := pathSeparator
+	a.Equal("[tag 1] folder1"+s+"*\n"+
+		"not folder1"+s+"file1\n"+
+		"not (?i)*.ex\n"+
+		"folder2"+s+"folder1"+s+"*.tmp\n"+
+		"*\n"+
+		"[ ] [file\n"+
+		"build"+s+"*"+s+"cache"+s+"*.tmp\n", ignoreList.String())
+	checkRoundTrip(a, ignoreList)
+
+	a.Equal("", NewList().String())
+}
+
+func TestWriteTo_gitignore(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := newGitignoreList(a, "# comment", "\\#file", "!/build/", "\\!important", "(?i)*.JPG", "a/**/b\\ ", "[abc]")
+	a.Equal("\\#file\n!/build/\n\\!important\n(?i)*.jpg\na/**/b\\ \n[abc]\n", ignoreList.String())
+	checkRoundTrip(a, ignoreList)
+}
+
+func TestWriteTo_roundTrip(t *testing.T) {
+	a := assert.New(t)
+	for _, lines := range matcherTestLists() {
+		ignoreList, err := NewListFromLines(lines)
+		a.NoError(err)
+		checkRoundTrip(a, ignoreList)
+	}
+	checkRoundTrip(a, generatedList(100))
+
+	// the patterns of Combine and AddPattern
+	ignoreList := generatedList(10)
+	ignoreList.AddPattern("[tag] not (?i)Folder1/*")
+	other, err := NewListFromLines([]string{"[other] generated/file2", "not not file"})
+	a.NoError(err)
+	ignoreList.Combine(other)
+	checkRoundTrip(a, ignoreList)
+
+	ignoreList = NewList()
+	ignoreList.SetOptions(Options{Separators: "/", CanonicalSeparator: '/'})
+	ignoreList.SetCaseInsensitive(true)
+	ignoreList.AddPattern("C:/Folder1\\*")
+	checkRoundTrip(a, ignoreList)
+
+	// the patterns which look like the directives
+	ignoreList = NewList()
+	for _, line := range []string{"@include x", "[] [@if x]", "[] [@end]", "[tag] @include x"} {
+		a.NoError(ignoreList.AddPattern(line))
+	}
+	checkRoundTrip(a, ignoreList)
+	checkRoundTrip(a, newGitignoreList(a, "@include x"))
+}
+
+func TestWriteTo_comments(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := NewList()
+	ignoreList.SetInlineComments(true)
+	a.NoError(ignoreList.LoadFromReader(strings.NewReader("# header\n\\#file1  #  comment 1 \n[tag] #file2\nnot *.ex\t#comment 2\n")))
+	a.Equal("\\#file1  # comment 1\n[tag] #file2\nnot *.ex  # comment 2\n", ignoreList.String())
+	checkRoundTrip(a, ignoreList)
+
+	// the comments are not written without the inline comments
+	ignoreList.SetInlineComments(false)
+	a.Equal("\\#file1  #  comment 1\n[tag] #file2\nnot *.ex\t#comment 2\n", ignoreList.String())
+	checkRoundTrip(a, ignoreList)
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
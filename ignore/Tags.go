@@ -0,0 +1,90 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"strings"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// Returns the union of the tags of all the patterns which match the given file path.
+// The tags are in the order of the patterns in the list and every tag is returned once,
+// both the include and the exclude patterns are used, see Match.Tags.
+// Use the Match method with the Tags field of its result to specify the type of the path explicitly.
+func (ignoreList *List) MatchedTags(filePath string) []string {
+	return ignoreList.Explain(filePath).Tags
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func (s *pattern) tags() []string {
+	return parseTags(s.tag)
+}
+
+// Parses the items of the tag which are separated with ",", the spaces around the items are removed.
+// The "key=value" items are the attributes (see parseAttributes) and the empty items are skipped.
+// It returns nil if the tag does not have any item.
+func parseTags(tag string) []string {
+	var tags []string
+	for _, item := range strings.Split(tag, attributeSeparator) {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 || strings.Contains(item, attributeAssign) {
+			continue
+		}
+		tags = appendTags(tags, item)
+	}
+	return tags
+}
+
+// Appends the tags which are not in the list yet.
+func appendTags(list []string, tags ...string) []string {
+	for _, tag := range tags {
+		found := false
+		for _, existing := range list {
+			if existing == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, tag)
+		}
+	}
+	return list
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
@@ -0,0 +1,88 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func TestParseTags(t *testing.T) {
+	a := assert.New(t)
+	a.Nil(parseTags(""))
+	a.Nil(parseTags(" , "))
+	a.Equal([]string{"Any text"}, parseTags("Any text"))
+	a.Equal([]string{"lod", "heavy"}, parseTags(" lod ,heavy, lod"))
+	a.Equal([]string{"audio"}, parseTags("reason=license, audio, owner=audio-team"))
+	a.Nil(parseTags("reason=license"))
+}
+
+func TestMatchedTags(t *testing.T) {
+	a := assert.New(t)
+	ignoreList, err := NewListFromString(strings.Join([]string{
+		"[heavy] big/*",
+		"[lod, model] *.obj",
+		"[reason=size, heavy] *.fbx",
+		"[release] not big/keep/*",
+		"big/*.tmp",
+	}, "\n"))
+	a.NoError(err)
+
+	a.Equal([]string{"heavy", "lod", "model"}, ignoreList.MatchedTags("big/a.obj"))
+	a.Equal([]string{"lod", "model"}, ignoreList.MatchedTags("small/a.obj"))
+	a.Equal([]string{"heavy"}, ignoreList.MatchedTags("big/a.fbx"))
+	// the tags of the include patterns are also returned
+	a.Equal([]string{"heavy", "lod", "model", "release"}, ignoreList.MatchedTags("big/keep/a.obj"))
+	a.Nil(ignoreList.MatchedTags("small/a.tmp"))
+	a.Nil(ignoreList.MatchedTags("../a.obj"))
+
+	match := ignoreList.Match("big/folder", true)
+	a.True(match.Ignored)
+	a.Equal([]string{"heavy"}, match.Tags)
+
+	rules := ignoreList.Rules()
+	a.Equal([]string{"lod", "model"}, rules[1].Tags)
+	a.Equal([]string{"heavy"}, rules[2].Tags)
+	a.Nil(rules[4].Tags)
+
+	// every tag of a pattern can activate it
+	a.True(ignoreList.IsIgnoredFor("a.obj", "model"))
+	a.False(ignoreList.IsIgnoredFor("a.obj", "lod, model"))
+	a.False(ignoreList.IsIgnoredFor("a.obj", "heavy"))
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
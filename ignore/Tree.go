@@ -0,0 +1,285 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// Tree is a set of ignore lists which are placed in different folders like .gitignore files.
+//
+// Every folder can contain an ignore file with the name specified for the tree.
+// The patterns of the file are relative to its folder and they are applied to the folder children only.
+// A path is checked with the lists of all its parent folders, the deeper list which has a matched pattern decides.
+// I.e. the list of the folder "some-folder" has priority over the list of the root folder
+// for the path "some-folder/file".
+//
+// The folders are always specified relative to the root of the tree, the root itself is "" or ".".
+// Unlike the List the tree must not be changed (loaded, walked or SetList) while it is used from other goroutines.
+type Tree struct {
+	fileName string
+	dialect  Dialect
+	options  Options
+	lists    map[string]*List
+}
+
+// Returns new ignore tree which reads ignore files with the specified name.
+func NewTree(fileName string) *Tree {
+	return NewTreeWithDialect(fileName, DialectNative)
+}
+
+// Returns new ignore tree which reads ignore files with the specified name and dialect.
+func NewTreeWithDialect(fileName string, dialect Dialect) *Tree {
+	return &Tree{fileName: fileName, dialect: dialect, lists: make(map[string]*List)}
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// Sets the ignore list for the folder, the patterns of the list must be relative to the folder.
+// Nil list removes the list of the folder.
+func (tree *Tree) SetList(folder string, list *List) {
+	if list == nil {
+		delete(tree.lists, tree.folderKey(folder))
+		return
+	}
+	tree.lists[tree.folderKey(folder)] = list
+}
+
+// Returns the ignore list of the folder or nil if the folder does not have it.
+func (tree *Tree) List(folder string) *List {
+	return tree.lists[tree.folderKey(folder)]
+}
+
+// Sets the options which are used to split the checked paths and the folders of the tree.
+// The lists which are loaded after the call get the same options, see List.SetOptions.
+// The lists which are set with SetList keep their own options.
+func (tree *Tree) SetOptions(options Options) {
+	tree.options = options
+}
+
+// Loads ignore files from all the folders of the operating system folder tree.
+// See LoadFS
+func (tree *Tree) Load(root string) error {
+	return tree.LoadFS(os.DirFS(root), ".")
+}
+
+// Loads ignore files from all the folders of the file system tree.
+// The ignore files which are inside the skipped folders are not loaded, see WalkFS.
+func (tree *Tree) LoadFS(fsys fs.FS, root string) error {
+	return tree.WalkFS(fsys, root, func(filePath string, entry fs.DirEntry, err error) error {
+		return err
+	})
+}
+
+// Walks the folder tree of the operating system and loads ignore files.
+// See WalkFS
+func (tree *Tree) Walk(root string, fn fs.WalkDirFunc) error {
+	return tree.WalkFS(os.DirFS(root), ".", fn)
+}
+
+// Walks the file tree of the file system like List.WalkFS does
+// but it loads the ignore file of every folder before visiting the folder children.
+// With DialectNative the ignore file of an ignored folder is loaded before deciding whether to skip the folder,
+// because its patterns can include the folder children. With DialectGitignore the ignored folders
+// are skipped entirely and their ignore files are not loaded.
+// The walking is stopped if an ignore file can not be loaded, the error is returned.
+func (tree *Tree) WalkFS(fsys fs.FS, root string, fn fs.WalkDirFunc) error {
+	return fs.WalkDir(fsys, root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return fn(filePath, entry, err)
+		}
+		// the list of the folder can include its children, so it is loaded before the folder is skipped
+		loaded := entry.IsDir() && tree.dialect == DialectNative
+		if loaded {
+			if err := tree.loadFolder(fsys, filePath, relativePath(root, filePath)); err != nil {
+				return err
+			}
+		}
+		ignored := false
+		if filePath != root {
+			relPath := fsPath(relativePath(root, filePath), entry.IsDir(), &tree.options)
+			if q, err := newQuery(&relPath, &tree.options); err == nil {
+				if ignored, _ = tree.isIgnored(q); ignored && entry.IsDir() && tree.isFolderPruned(q) {
+					return fs.SkipDir
+				}
+			}
+		}
+		if entry.IsDir() && !loaded {
+			if err := tree.loadFolder(fsys, filePath, relativePath(root, filePath)); err != nil {
+				return err
+			}
+		}
+		if ignored {
+			return nil
+		}
+		return fn(filePath, entry, nil)
+	})
+}
+
+// It returns true if the given file path is ignored by the lists of the tree otherwise false.
+// See IsIgnoredEx
+func (tree *Tree) IsIgnored(filePath string) bool {
+	res, _ := tree.IsIgnoredEx(filePath)
+	return res
+}
+
+// It returns true if the given folder path is ignored by the lists of the tree otherwise false.
+// See List.IsIgnoredDir
+func (tree *Tree) IsIgnoredDir(folderPath string) bool {
+	q, err := newQuery(&folderPath, &tree.options)
+	if err != nil {
+		return false
+	}
+	res, _ := tree.isIgnored(q.withType(true))
+	return res
+}
+
+// It returns true if the given file path is ignored by the lists of the tree otherwise false.
+// Also it returns the tag of the pattern which decided.
+// The path must be relative to the root of the tree or it must be inside the base folder, see SetOptions.
+//
+// With DialectGitignore a file can not be included if one of its parent folders is ignored.
+func (tree *Tree) IsIgnoredEx(filePath string) (bool, string) {
+	q, err := newQuery(&filePath, &tree.options)
+	if err != nil {
+		return false, ""
+	}
+	return tree.isIgnored(q)
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// Returns the folder key of the lists map.
+func (tree *Tree) folderKey(folder string) string {
+	q, err := newQuery(&folder, &tree.options)
+	if err != nil {
+		// the folder outside the root never has patterns which are used
+		return folder
+	}
+	return strings.Join(q.elements, "/")
+}
+
+func (tree *Tree) loadFolder(fsys fs.FS, filePath string, relPath string) error {
+	list := NewListWithDialect(tree.dialect)
+	list.SetOptions(tree.options)
+	err := list.LoadFromFS(fsys, path.Join(filePath, tree.fileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	tree.lists[tree.folderKey(fsPath(relPath, false, &tree.options))] = list
+	return nil
+}
+
+func (tree *Tree) isIgnored(q *query) (bool, string) {
+	if tree.dialect == DialectGitignore {
+		for i := 1; i < len(q.elements); i++ {
+			if list, _, idx := tree.decisivePattern(q.parent(i)); idx != -1 && !list.patternList[idx].include {
+				return true, list.patternList[idx].tag
+			}
+		}
+	}
+	if list, _, idx := tree.decisivePattern(q); idx != -1 {
+		p := &list.patternList[idx]
+		return !p.include, p.tag
+	}
+	return false, ""
+}
+
+// Returns the snapshot of the list which decides, the query relative to the list folder
+// and index of the decisive pattern. The index is -1 if none of the lists has a matched pattern.
+func (tree *Tree) decisivePattern(q *query) (*listState, *query, int) {
+	for i := len(q.elements) - 1; i >= 0; i-- {
+		list, ok := tree.lists[strings.Join(q.elements[:i], "/")]
+		if !ok {
+			continue
+		}
+		state := list.snapshot()
+		_, separator := state.options.separators()
+		relQuery, err := state.query(q.relativePath(i, separator))
+		if err != nil {
+			continue
+		}
+		if idx := state.decisivePattern(relQuery); idx != -1 {
+			return state, relQuery, idx
+		}
+	}
+	return nil, nil, -1
+}
+
+// Returns true if all the paths inside the ignored folder are ignored too.
+func (tree *Tree) isFolderPruned(q *query) bool {
+	if tree.dialect == DialectGitignore {
+		return true
+	}
+	list, relQuery, idx := tree.decisivePattern(q)
+	if idx == -1 || !list.isFolderPruned(relQuery) {
+		return false
+	}
+	// the include patterns of the other lists from the root to the folder can also match the paths inside it
+	for i := 0; i <= len(q.elements); i++ {
+		list, ok := tree.lists[strings.Join(q.elements[:i], "/")]
+		if !ok {
+			continue
+		}
+		state := list.snapshot()
+		if i == len(q.elements) {
+			// the list of the folder itself
+			if state.hasInclude() {
+				return false
+			}
+			continue
+		}
+		_, separator := state.options.separators()
+		relQuery, err := state.query(q.relativePath(i, separator))
+		if err == nil && state.canIncludeInFolder(relQuery) {
+			return false
+		}
+	}
+	return true
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
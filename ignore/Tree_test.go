@@ -0,0 +1,240 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func treeFS() fstest.MapFS {
+	return fstest.MapFS{
+		"project/.myignore":                {Data: []byte("[root] *.tmp\n*.log\nbuild/*\n")},
+		"project/a.tmp":                    {},
+		"project/a.txt":                    {},
+		"project/build/out.bin":            {},
+		"project/build/.myignore":          {Data: []byte("not *.bin\n")},
+		"project/src/.myignore":            {Data: []byte("[src] not keep.tmp\nmain.go\n")},
+		"project/src/keep.tmp":             {},
+		"project/src/main.go":              {},
+		"project/src/debug.log":            {},
+		"project/src/sub/main.go":          {},
+		"project/src/sub/keep.tmp":         {},
+		"project/src/sub/.myignore":        {Data: []byte("*.log\nnot *.tmp\n")},
+		"project/src/sub/debug.log":        {},
+		"project/doc/readme.md":            {},
+		"project/doc/generated/.myignore":  {Data: []byte("[tag\n")},
+		"project/doc/generated/index.html": {},
+	}
+}
+
+func walkedTreePaths(a *assert.Assertions, tree *Tree, fsys fs.FS, root string) ([]string, error) {
+	var paths []string
+	err := tree.WalkFS(fsys, root, func(filePath string, entry fs.DirEntry, err error) error {
+		a.NoError(err)
+		paths = append(paths, filePath)
+		return nil
+	})
+	return paths, err
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func TestTreeIsIgnored(t *testing.T) {
+	a := assert.New(t)
+	tree := NewTree(".myignore")
+	rootList, _ := NewListFromLines([]string{"[root] *.tmp", "folder1/*"})
+	subList, _ := NewListFromLines([]string{"[sub] not *keep.tmp", "file1"})
+	tree.SetList(".", rootList)
+	tree.SetList("folder2\\", subList)
+	a.Equal(subList, tree.List("folder2"))
+	a.Nil(tree.List("folder1"))
+
+	a.True(tree.IsIgnored("a.tmp"))
+	a.True(tree.IsIgnored("folder2/keep.tmp/a.tmp"))
+	res, tag := tree.IsIgnoredEx("folder2/keep.tmp")
+	a.False(res)
+	a.Equal("sub", tag)
+	a.True(tree.IsIgnored("folder2/file1"))
+	a.False(tree.IsIgnored("file1"))
+	a.True(tree.IsIgnored("folder1/file1"))
+
+	// the list of the sub folder does not match the path, so the root list decides
+	res, tag = tree.IsIgnoredEx("folder2/folder1/a.tmp")
+	a.True(res)
+	a.Equal("root", tag)
+
+	tree.SetList("folder2", nil)
+	a.True(tree.IsIgnored("folder2/keep.tmp"))
+}
+
+func TestTreeWalkFS(t *testing.T) {
+	a := assert.New(t)
+	tree := NewTree(".myignore")
+	paths, err := walkedTreePaths(a, tree, treeFS(), "project")
+	var parseErr *ParseError
+	if a.True(errors.As(err, &parseErr)) {
+		a.Equal("project/doc/generated/.myignore", parseErr.File)
+	}
+
+	fsys := treeFS()
+	delete(fsys, "project/doc/generated/.myignore")
+	tree = NewTree(".myignore")
+	paths, err = walkedTreePaths(a, tree, fsys, "project")
+	a.NoError(err)
+	a.Equal([]string{
+		"project",
+		"project/.myignore",
+		"project/a.txt",
+		"project/build/out.bin",
+		"project/doc",
+		"project/doc/generated",
+		"project/doc/generated/index.html",
+		"project/doc/readme.md",
+		"project/src",
+		"project/src/.myignore",
+		"project/src/keep.tmp",
+		"project/src/sub",
+		"project/src/sub/.myignore",
+		"project/src/sub/keep.tmp",
+		"project/src/sub/main.go",
+	}, paths)
+	a.NotNil(tree.List("."))
+	a.NotNil(tree.List("src/sub"))
+	// the ignore file of the ignored folder is loaded, it includes the folder children
+	a.NotNil(tree.List("build"))
+	a.False(tree.IsIgnored("build/out.bin"))
+
+	res, tag := tree.IsIgnoredEx("src/keep.tmp")
+	a.False(res)
+	a.Equal("src", tag)
+	a.True(tree.IsIgnored("src/sub/debug.log"))
+}
+
+func TestTreeWalkFS_include(t *testing.T) {
+	a := assert.New(t)
+	fsys := fstest.MapFS{
+		".myignore":   {Data: []byte("a/b/*\n")},
+		"a/.myignore": {Data: []byte("not b/keep\n")},
+		"a/b/keep":    {},
+		"a/b/other":   {},
+		"c/.myignore": {Data: []byte("not d/keep\n")},
+		"c/d/keep":    {},
+		// the list of the ignored folder includes its children
+		"build/.myignore": {Data: []byte("not keep.txt\n")},
+		"build/keep.txt":  {},
+		"build/out.bin":   {},
+	}
+	fsys[".myignore"] = &fstest.MapFile{Data: []byte("a/b/*\nbuild/*\n")}
+	tree := NewTree(".myignore")
+	paths, err := walkedTreePaths(a, tree, fsys, ".")
+	a.NoError(err)
+	a.False(tree.IsIgnored("a/b/keep"))
+	a.True(tree.IsIgnored("a/b/other"))
+	a.False(tree.IsIgnored("build/keep.txt"))
+	a.True(tree.IsIgnored("build/out.bin"))
+	a.Equal([]string{".", ".myignore", "a", "a/.myignore", "a/b/keep", "build/keep.txt", "c", "c/.myignore", "c/d",
+		"c/d/keep"}, paths)
+
+	loaded := NewTree(".myignore")
+	a.NoError(loaded.LoadFS(fsys, "."))
+	a.False(loaded.IsIgnored("build/keep.txt"))
+}
+
+func TestTreeGitignore(t *testing.T) {
+	a := assert.New(t)
+	fsys := fstest.MapFS{
+		".myignore":             {Data: []byte("build/\n*.tmp\n")},
+		"build/.myignore":       {Data: []byte("!*\n")},
+		"build/out.bin":         {},
+		"src/.myignore":         {Data: []byte("!keep.tmp\n/gen/\n")},
+		"src/keep.tmp":          {},
+		"src/a.tmp":             {},
+		"src/gen/a.go":          {},
+		"src/sub/gen/a.go":      {},
+		"src/sub/keep.tmp":      {},
+		"src/sub/deep/keep.tmp": {},
+	}
+	tree := NewTreeWithDialect(".myignore", DialectGitignore)
+	paths, err := walkedTreePaths(a, tree, fsys, ".")
+	a.NoError(err)
+	a.Equal([]string{
+		".",
+		".myignore",
+		"src",
+		"src/.myignore",
+		"src/keep.tmp",
+		"src/sub",
+		"src/sub/deep",
+		"src/sub/deep/keep.tmp",
+		"src/sub/gen",
+		"src/sub/gen/a.go",
+		"src/sub/keep.tmp",
+	}, paths)
+	a.Nil(tree.List("build"))
+	a.True(tree.IsIgnored("build/out.bin"))
+}
+
+func TestTreeLoad(t *testing.T) {
+	a := assert.New(t)
+	root := t.TempDir()
+	a.NoError(os.MkdirAll(filepath.Join(root, "folder1", "folder2"), 0755))
+	a.NoError(os.WriteFile(filepath.Join(root, ".myignore"), []byte("*.tmp\n"), 0644))
+	a.NoError(os.WriteFile(filepath.Join(root, "folder1", "folder2", ".myignore"), []byte("not a.tmp\n"), 0644))
+
+	tree := NewTree(".myignore")
+	a.NoError(tree.Load(root))
+	a.True(tree.IsIgnored("folder1/a.tmp"))
+	a.False(tree.IsIgnored("folder1/folder2/a.tmp"))
+	a.True(tree.IsIgnored("folder1/folder2/b.tmp"))
+
+	var paths []string
+	err := tree.Walk(root, func(filePath string, entry fs.DirEntry, err error) error {
+		paths = append(paths, filePath)
+		return err
+	})
+	a.NoError(err)
+	a.Equal([]string{".", ".myignore", "folder1", "folder1/folder2", "folder1/folder2/.myignore"}, paths)
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
@@ -0,0 +1,115 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"io/fs"
+	"os"
+	"strings"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// Walks the folder tree of the operating system like filepath.WalkDir does
+// but it does not call the function for the ignored files and folders
+// and it does not visit the ignored folders if none of their children can be included.
+//
+// The function gets the paths relative to the root with "/" separator, the root itself is ".".
+// See WalkFS
+func (ignoreList *List) Walk(root string, fn fs.WalkDirFunc) error {
+	return ignoreList.WalkFS(os.DirFS(root), ".", fn)
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// Returns true if the pattern matches all the paths inside the folder.
+func (s *pattern) coversFolder(q *query) bool {
+	if s.glob != nil || s.isFile || s.HasSuffix() || len(s.segments) != 0 {
+		return false
+	}
+	return strings.HasPrefix(s.queryFor(q).path, s.prefix)
+}
+
+// Returns true if the pattern can match at least one path inside the folder.
+func (s *pattern) canMatchInFolder(q *query) bool {
+	if s.glob != nil {
+		return true
+	}
+	folder := s.queryFor(q).path
+	return strings.HasPrefix(s.prefix, folder) || strings.HasPrefix(folder, s.prefix)
+}
+
+// Returns true if all the paths inside the ignored folder are ignored too.
+func (state *listState) isFolderPruned(q *query) bool {
+	if state.dialect == DialectGitignore && state.actualEvaluation() == EvaluationLastMatch {
+		// it is not possible to include a file if its parent folder is ignored
+		return true
+	}
+	// the children inherit the ignoring of the folder
+	covered := state.inherits()
+	for i := range state.patternList {
+		p := &state.patternList[i]
+		if p.include {
+			if p.canMatchInFolder(q) {
+				return false
+			}
+		} else if !covered {
+			covered = p.coversFolder(q)
+		}
+	}
+	return covered
+}
+
+// Returns true if an include pattern can match at least one path inside the folder.
+func (state *listState) canIncludeInFolder(q *query) bool {
+	for i := range state.patternList {
+		if p := &state.patternList[i]; p.include && p.canMatchInFolder(q) {
+			return true
+		}
+	}
+	return false
+}
+
+func (state *listState) hasInclude() bool {
+	for i := range state.patternList {
+		if state.patternList[i].include {
+			return true
+		}
+	}
+	return false
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
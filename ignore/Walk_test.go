@@ -0,0 +1,171 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// The file system remembers which folders were read.
+type recordFS struct {
+	fsys   fstest.MapFS
+	opened []string
+}
+
+func (r *recordFS) Open(name string) (fs.File, error) {
+	if info, err := fs.Stat(r.fsys, name); err == nil && info.IsDir() {
+		r.opened = append(r.opened, name)
+	}
+	return r.fsys.Open(name)
+}
+
+func walkTreeFS() fstest.MapFS {
+	return fstest.MapFS{
+		"node_modules/a/index.js":    {},
+		"node_modules/b/index.js":    {},
+		"node_modules/b/readme.md":   {},
+		"src/main.js":                {},
+		"src/cache/data.tmp":         {},
+		"assets/big/model.obj":       {},
+		"assets/big/model.keep.obj":  {},
+		"assets/small/model.obj":     {},
+		"assets/small/model.mtl.tmp": {},
+	}
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func TestWalkPruning_case1(t *testing.T) {
+	a := assert.New(t)
+	ignoreList, err := NewListFromLines([]string{"node_modules/*", "*.tmp"})
+	a.NoError(err)
+	fsys := &recordFS{fsys: walkTreeFS()}
+	a.Equal([]string{
+		".",
+		"assets",
+		"assets/big",
+		"assets/big/model.keep.obj",
+		"assets/big/model.obj",
+		"assets/small",
+		"assets/small/model.obj",
+		"src",
+		"src/cache",
+		"src/main.js",
+	}, walkedPaths(a, ignoreList, fsys, "."))
+	a.NotContains(fsys.opened, "node_modules")
+	a.Contains(fsys.opened, "src/cache")
+}
+
+// The include pattern can include a file inside the ignored folder
+func TestWalkPruning_case2(t *testing.T) {
+	a := assert.New(t)
+	ignoreList, err := NewListFromLines([]string{"node_modules/*", "assets/*", "not node_modules/b/*.md"})
+	a.NoError(err)
+	fsys := &recordFS{fsys: walkTreeFS()}
+	a.Equal([]string{
+		".",
+		"node_modules/b/readme.md",
+		"src",
+		"src/cache",
+		"src/cache/data.tmp",
+		"src/main.js",
+	}, walkedPaths(a, ignoreList, fsys, "."))
+	a.Contains(fsys.opened, "node_modules")
+	a.Contains(fsys.opened, "node_modules/b")
+	a.NotContains(fsys.opened, "assets")
+}
+
+// The exclude pattern which does not match all the children does not prune the folder
+func TestWalkPruning_case3(t *testing.T) {
+	a := assert.New(t)
+	ignoreList, err := NewListFromLines([]string{"*/", "*.obj"})
+	a.NoError(err)
+	fsys := &recordFS{fsys: walkTreeFS()}
+	paths := walkedPaths(a, ignoreList, fsys, "assets")
+	a.Equal([]string{"assets", "assets/small/model.mtl.tmp"}, paths)
+	a.Contains(fsys.opened, "assets/big")
+}
+
+func TestWalkPruning_gitignore(t *testing.T) {
+	a := assert.New(t)
+	ignoreList := newGitignoreList(a, "node_modules/", "!node_modules/b/readme.md", "big/", "*.tmp")
+	fsys := &recordFS{fsys: walkTreeFS()}
+	a.Equal([]string{
+		".",
+		"assets",
+		"assets/small",
+		"assets/small/model.obj",
+		"src",
+		"src/cache",
+		"src/main.js",
+	}, walkedPaths(a, ignoreList, fsys, "."))
+	a.NotContains(fsys.opened, "node_modules")
+	a.NotContains(fsys.opened, "assets/big")
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func TestWalk(t *testing.T) {
+	a := assert.New(t)
+	root := t.TempDir()
+	for _, name := range []string{"folder1/file1", "folder1/file2", "folder2/folder1/file1", "folder2/folder2/file1"} {
+		filePath := filepath.Join(root, filepath.FromSlash(name))
+		a.NoError(os.MkdirAll(filepath.Dir(filePath), 0755))
+		a.NoError(os.WriteFile(filePath, nil, 0644))
+	}
+	ignoreList, err := NewListFromLines([]string{"folder2\\*", "not folder2:folder1/file1", "*2"})
+	a.NoError(err)
+
+	var paths []string
+	err = ignoreList.Walk(root, func(filePath string, entry fs.DirEntry, err error) error {
+		a.NoError(err)
+		paths = append(paths, filePath)
+		return nil
+	})
+	a.NoError(err)
+	a.Equal([]string{".", "folder1", "folder1/file1", "folder2/folder1/file1"}, paths)
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
@@ -0,0 +1,156 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// The interval of Watch which is used if the specified one is not positive.
+const defaultWatchInterval = time.Second
+
+// Watcher reloads the ignore list when its file is changed.
+// It checks modification time and size of the file periodically, so it does not need any notifications
+// from the operating system. The new patterns replace the old ones atomically, see List.
+//
+// If the changed file can not be loaded the list keeps the last good patterns
+// and the error is reported to the callback. In the lenient mode the correct patterns
+// of the changed file are used and the problems are reported as ParseErrors.
+// Only the watched file is checked, the changes of the files which it includes
+// are loaded when the watched file is changed.
+type Watcher struct {
+	list     *List
+	filePath string
+	onError  func(err error)
+
+	mutex   sync.Mutex
+	exists  bool
+	modTime time.Time
+	size    int64
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// Starts watching the file with the specified interval,
+// the interval is 1 second (defaultWatchInterval) if the specified one is zero or negative.
+// The list is not loaded by this method, it is expected that the list is already loaded from the file.
+// The callback is called from the watching goroutine, it can be nil.
+// Call Stop when the watching is not needed anymore.
+func (ignoreList *List) Watch(filePath string, interval time.Duration, onError func(err error)) *Watcher {
+	w := &Watcher{
+		list:     ignoreList,
+		filePath: filePath,
+		onError:  onError,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if info, err := os.Stat(filePath); err == nil {
+		w.exists, w.modTime, w.size = true, info.ModTime(), info.Size()
+	}
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	go w.run(interval)
+	return w
+}
+
+// Stops watching, it waits until the watching goroutine is finished.
+// It is safe to call it several times.
+func (w *Watcher) Stop() {
+	w.stopOnce.Do(func() { close(w.stop) })
+	<-w.done
+}
+
+// Checks the file immediately without waiting for the interval.
+// It returns true if the file was changed and the list was reloaded.
+// The error is returned as well as it is reported to the callback.
+func (w *Watcher) Check() (bool, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	info, err := os.Stat(w.filePath)
+	if err != nil {
+		if !w.exists {
+			return false, nil
+		}
+		// the file is removed, the last patterns are kept
+		w.exists = false
+		return false, w.report(err)
+	}
+	if w.exists && info.ModTime().Equal(w.modTime) && info.Size() == w.size {
+		return false, nil
+	}
+	file, err := os.Open(w.filePath)
+	if err != nil {
+		// the file is checked again next time
+		return false, w.report(err)
+	}
+	defer file.Close()
+	// the file with incorrect patterns is not loaded again until it is changed
+	w.exists, w.modTime, w.size = true, info.ModTime(), info.Size()
+	err = w.list.load(file, w.filePath, osSource{}, true)
+	if _, ok := err.(ParseErrors); err != nil && !ok {
+		return false, w.report(err)
+	}
+	return true, w.report(err)
+}
+
+func (w *Watcher) run(interval time.Duration) {
+	defer close(w.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.Check()
+		}
+	}
+}
+
+func (w *Watcher) report(err error) error {
+	if err != nil && w.onError != nil {
+		w.onError(err)
+	}
+	return err
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
@@ -0,0 +1,207 @@
+/*
+**  Copyright(C) 2017, StepToSky
+**
+**  Redistribution and use in source and binary forms, with or without
+**  modification, are permitted provided that the following conditions are met:
+**
+**  1.Redistributions of source code must retain the above copyright notice, this
+**    list of conditions and the following disclaimer.
+**  2.Redistributions in binary form must reproduce the above copyright notice,
+**    this list of conditions and the following disclaimer in the documentation
+**    and / or other materials provided with the distribution.
+**  3.Neither the name of StepToSky nor the names of its contributors
+**    may be used to endorse or promote products derived from this software
+**    without specific prior written permission.
+**
+**  THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND
+**  ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED
+**  WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+**  DISCLAIMED.IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR
+**  ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES
+**  (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES;
+**  LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER CAUSED AND
+**  ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY, OR TORT
+**  (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE OF THIS
+**  SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+**
+**  Contacts: www.steptosky.com
+ */
+
+package ignore
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+// Writes the file and moves its modification time forward, so the change is always noticed.
+func writeWatchedFile(a *assert.Assertions, filePath string, data string, step int) {
+	a.NoError(os.WriteFile(filePath, []byte(data), 0644))
+	modTime := time.Now().Add(time.Duration(step) * time.Hour)
+	a.NoError(os.Chtimes(filePath, modTime, modTime))
+}
+
+type errorRecorder struct {
+	mutex  sync.Mutex
+	errors []error
+}
+
+func (r *errorRecorder) record(err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.errors = append(r.errors, err)
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/
+
+func TestWatcherCheck(t *testing.T) {
+	a := assert.New(t)
+	watchedFile := filepath.Join(t.TempDir(), "ignore")
+	writeWatchedFile(a, watchedFile, "folder1/*\n", 0)
+	ignoreList, err := NewListFromFile(watchedFile)
+	a.NoError(err)
+
+	recorder := &errorRecorder{}
+	watcher := ignoreList.Watch(watchedFile, time.Hour, recorder.record)
+	defer watcher.Stop()
+	fpList := filePathList()
+
+	// not changed
+	res, err := watcher.Check()
+	a.False(res)
+	a.NoError(err)
+
+	// changed
+	writeWatchedFile(a, watchedFile, "folder2/*\n", 1)
+	res, err = watcher.Check()
+	a.True(res)
+	a.NoError(err)
+	a.False(ignoreList.IsIgnored(fpList[0])) // "folder1/file1"
+	a.True(ignoreList.IsIgnored(fpList[2]))  // "folder2/folder1/file1"
+
+	// incorrect, the last good patterns are kept
+	writeWatchedFile(a, watchedFile, "folder1/*\n[tag\n", 2)
+	res, err = watcher.Check()
+	a.False(res)
+	a.True(errors.Is(err, ErrTagNotClosed))
+	a.False(ignoreList.IsIgnored(fpList[0])) // "folder1/file1"
+	a.True(ignoreList.IsIgnored(fpList[2]))  // "folder2/folder1/file1"
+
+	// removed, the last good patterns are kept
+	a.NoError(os.Remove(watchedFile))
+	res, err = watcher.Check()
+	a.False(res)
+	a.True(errors.Is(err, os.ErrNotExist))
+	res, err = watcher.Check()
+	a.False(res)
+	a.NoError(err)
+	a.True(ignoreList.IsIgnored(fpList[2])) // "folder2/folder1/file1"
+
+	// created again
+	writeWatchedFile(a, watchedFile, "folder1/*\n", 3)
+	res, err = watcher.Check()
+	a.True(res)
+	a.NoError(err)
+	a.True(ignoreList.IsIgnored(fpList[0]))  // "folder1/file1"
+	a.False(ignoreList.IsIgnored(fpList[2])) // "folder2/folder1/file1"
+
+	if a.Len(recorder.errors, 2) {
+		a.True(errors.Is(recorder.errors[0], ErrTagNotClosed))
+		a.True(errors.Is(recorder.errors[1], os.ErrNotExist))
+	}
+}
+
+func TestWatcherCheck_lenient(t *testing.T) {
+	a := assert.New(t)
+	watchedFile := filepath.Join(t.TempDir(), "ignore")
+	writeWatchedFile(a, watchedFile, "folder1/*\n", 0)
+	ignoreList, err := NewListFromFile(watchedFile)
+	a.NoError(err)
+	ignoreList.SetLenient(true)
+	watcher := ignoreList.Watch(watchedFile, time.Hour, nil)
+	defer watcher.Stop()
+
+	writeWatchedFile(a, watchedFile, "folder2/*\n[tag\n", 1)
+	res, err := watcher.Check()
+	a.True(res)
+	var parseErrors ParseErrors
+	a.True(errors.As(err, &parseErrors))
+	a.False(ignoreList.IsIgnored("folder1/file1"))
+	a.True(ignoreList.IsIgnored("folder2/file1"))
+}
+
+func TestWatcherCheck_openError(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("the permissions do not restrict the root user")
+	}
+	a := assert.New(t)
+	watchedFile := filepath.Join(t.TempDir(), "ignore")
+	writeWatchedFile(a, watchedFile, "folder1/*\n", 0)
+	ignoreList, err := NewListFromFile(watchedFile)
+	a.NoError(err)
+	watcher := ignoreList.Watch(watchedFile, time.Hour, nil)
+	defer watcher.Stop()
+
+	writeWatchedFile(a, watchedFile, "folder2/*\n", 1)
+	a.NoError(os.Chmod(watchedFile, 0))
+	res, err := watcher.Check()
+	a.False(res)
+	a.ErrorIs(err, fs.ErrPermission)
+	a.True(ignoreList.IsIgnored("folder1/file1"))
+
+	// the file is loaded when it can be opened even if it is not changed
+	a.NoError(os.Chmod(watchedFile, 0644))
+	res, err = watcher.Check()
+	a.True(res)
+	a.NoError(err)
+	a.True(ignoreList.IsIgnored("folder2/file1"))
+}
+
+func TestWatcherInterval(t *testing.T) {
+	a := assert.New(t)
+	watchedFile := filepath.Join(t.TempDir(), "ignore")
+	writeWatchedFile(a, watchedFile, "folder1/*\n", 0)
+	ignoreList, err := NewListFromFile(watchedFile)
+	a.NoError(err)
+	watcher := ignoreList.Watch(watchedFile, time.Millisecond, nil)
+
+	writeWatchedFile(a, watchedFile, "folder2/*\n", 1)
+	deadline := time.Now().Add(5 * time.Second)
+	for !ignoreList.IsIgnored("folder2/file1") && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	a.True(ignoreList.IsIgnored("folder2/file1"))
+	a.False(ignoreList.IsIgnored("folder1/file1"))
+
+	watcher.Stop()
+	watcher.Stop()
+	writeWatchedFile(a, watchedFile, "folder1/*\n", 2)
+	time.Sleep(10 * time.Millisecond)
+	a.False(ignoreList.IsIgnored("folder1/file1"))
+
+	// the default interval is used instead of the incorrect one
+	for i, interval := range []time.Duration{0, -time.Second} {
+		watcher = ignoreList.Watch(watchedFile, interval, nil)
+		writeWatchedFile(a, watchedFile, "folder2/*\n", 3+i)
+		res, err := watcher.Check()
+		a.True(res)
+		a.NoError(err)
+		watcher.Stop()
+	}
+}
+
+/*********************************************************************************************************/
+///////////////////////////////////////////////////////////////////////////////////////////////////////////
+/*********************************************************************************************************/